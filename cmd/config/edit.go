@@ -4,9 +4,11 @@ Copyright © 2025 Takeru Furuse
 package config
 
 import (
+	"bufio"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -17,7 +19,9 @@ import (
 var EditCmd = &cobra.Command{
 	Use:   "edit",
 	Short: "Edit the configuration file",
-	Long:  `Open the current configuration file in the default editor.`,
+	Long: `Open the current configuration file in the default editor.
+When the editor exits, the file is validated; if it has errors, they are shown
+and the editor can be reopened to fix them.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configFile := viper.ConfigFileUsed()
 		if configFile == "" {
@@ -41,15 +45,38 @@ var EditCmd = &cobra.Command{
 			editor = "vim" // default editor
 		}
 
-		editorCmd := exec.Command(editor, configFile)
-		editorCmd.Stdin = os.Stdin
-		editorCmd.Stdout = os.Stdout
-		editorCmd.Stderr = os.Stderr
+		for {
+			editorCmd := exec.Command(editor, configFile)
+			editorCmd.Stdin = os.Stdin
+			editorCmd.Stdout = os.Stdout
+			editorCmd.Stderr = os.Stderr
 
-		if err := editorCmd.Run(); err != nil {
-			return fmt.Errorf("failed to open editor: %w", err)
-		}
+			if err := editorCmd.Run(); err != nil {
+				return fmt.Errorf("failed to open editor: %w", err)
+			}
 
-		return nil
+			if _, err := os.Stat(configFile); os.IsNotExist(err) {
+				return nil // Nothing was saved
+			}
+			err := config.Validate(configFile)
+			if err == nil {
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, err)
+			if !confirm("Reopen the editor? [Y/n] ") {
+				os.Exit(1)
+			}
+		}
 	},
 }
+
+// confirm asks a yes or no question on the terminal. An empty answer is yes.
+func confirm(prompt string) bool {
+	fmt.Fprint(os.Stderr, prompt)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "" || answer == "y" || answer == "yes"
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// GetCmd represents the get command
+var GetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a configuration value",
+	Long: `Print the effective value of a setting, given as a dotted key such as work_duration,
+tts.engine or hooks.work[0]. The value comes from the configuration file, the environment
+or the defaults. Maps and lists are printed as YAML. Exits with status 1 if the setting is not set.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, ok, err := config.Get(args[0])
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		if !ok {
+			slog.Error(fmt.Sprintf("%s is not set", args[0]))
+			os.Exit(1)
+		}
+
+		switch value.(type) {
+		case map[string]any, []any, []string:
+			enc := yaml.NewEncoder(os.Stdout)
+			enc.SetIndent(2)
+			if err := enc.Encode(value); err != nil {
+				slog.Error("Failed to encode value", "error", err)
+				os.Exit(1)
+			}
+		default:
+			fmt.Println(strings.TrimSpace(fmt.Sprint(value)))
+		}
+	},
+}
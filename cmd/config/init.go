@@ -15,6 +15,7 @@ import (
 
 const defaultConfigTemplate = `# pmdr configuration file
 # For more information, see: https://github.com/tsuperis3112/pmdr
+# Check this file for mistakes with: pmdr config validate
 
 # Timer durations (any valid Go time duration string, e.g., "25m", "1h30m")
 work_duration: 25m
@@ -24,20 +25,164 @@ long_break_duration: 15m
 # Number of work cycles before a long break
 pomo_cycles: 4
 
-# Hooks: execute shell commands on events
+# Limits for "pmdr extend" (0s disables a limit)
+extend:
+  # Longest a session may become, excluding pauses
+  max_duration: 0s
+  # Shortest time that must remain after shortening a session
+  min_remaining: 0s
+
+# Wait for "pmdr next" when a session ends instead of starting the next one.
+# Session types that are not listed use the default.
+manual_transition:
+  default: false
+  # work: true
+  # short_break: false
+  # long_break: false
+
+# Spoken warnings before a session ends, which also run the warning hooks.
+# A plain list (e.g. "warnings: [2m, 30s]") applies to every session type.
+# Session types that are not listed use the default; an empty list disables them.
+warnings:
+  default: []
+  # work: [2m, 30s]
+  # long_break: [1m]
+
+# Native desktop notifications. When enabled, the start, complete, warning and
+# goal_reached events show a notification unless they are disabled below.
+# Any hook event can be configured; title and body are templates like hook commands.
+desktop:
+  enabled: false
+  # icon: /path/to/icon.png
+  events:
+    # complete:
+    #   title: "{{human .SessionType}} complete"
+    #   body: "Done after {{duration .Elapsed}}"
+    #   urgency: normal  # low, normal or critical
+    # start:
+    #   disabled: true
+
+# Spoken notifications go through a chain of backends, tried in order until one succeeds.
+# The built-in backends are tts, beep and desktop; more can be defined under notifiers
+# with the types command, file and webhook.
+notifiers:
+  # log:
+  #   type: file
+  #   path: ~/.local/state/pmdr/notifications.log
+  # phone:
+  #   type: webhook
+  #   url: https://ntfy.sh/my-pomodoro
+  # say:
+  #   type: command
+  #   command: 'echo "$PMDR_MESSAGE" | my-tts'
+notify:
+  chain: [tts, beep]
+  # Per-event chains; an empty chain silences the event.
+  events:
+    # warning: [beep]
+    # goal_reached: [tts, phone]
+
+# Text-to-speech engine: auto, say, spd-say, espeak-ng, piper, festival or powershell.
+# A custom command replaces the engine; "{{.Message}}" in its arguments is replaced
+# with the text, which is otherwise passed on stdin.
+tts:
+  engine: auto
+  # command: [mimic, -voice, slt, -t, "{{.Message}}"]
+  # voice: en-us          # For piper, the path of the voice model
+  # rate: 1.2             # Relative to the engine's default
+  # volume: 0.8           # Relative to the engine's default, up to 2
+  # Spoken messages per event, rendered like hook commands. An empty message silences the event.
+  messages:
+    # start: "{{human .SessionType}} started{{with .Task}}: {{.}}{{end}}."
+    # warning: "{{spell .Remaining}} left."
+    # complete: ""
+
+# Sounds and speech are silenced every day in this time range, which may wrap around
+# midnight. Hooks, desktop notifications and silent backends still run.
+# quiet_hours:
+#   start: "22:00"
+#   end: "07:00"
+
+# Daily goal shown in "pmdr status" (0 disables a target)
+daily_goal:
+  # Completed work sessions per day
+  pomodoros: 0
+  # Focused time per day
+  focus: 0s
+
+# Named profiles override any of the settings above, including hooks.
+# Select one with "pmdr start --profile NAME".
+# default_profile: deep-work
+profiles:
+  # deep-work:
+  #   work_duration: 50m
+  #   short_break_duration: 10m
+  # review:
+  #   work_duration: 25m
+  #   short_break_duration: 5m
+
+# Sequences replace the work/short break/long break cycle with custom steps.
+# Each step has a type (work, short_break or long_break), a duration, an optional name
+# and hooks that run when it completes. Select one with "pmdr start --sequence NAME".
+# sequence: focus-day
+sequences:
+  # focus-day:
+  #   loop: false
+  #   steps:
+  #     - {name: morning, type: work, duration: 90m}
+  #     - {type: short_break, duration: 20m}
+  #     - {type: work, duration: 90m}
+  #     - {name: lunch, type: long_break, duration: 60m, hooks: ["echo lunch is over"]}
+
+# Hooks: execute shell commands on events, keyed as hooks.<event>.<session_type>.
+# Events: start, pause, resume, stop, skip, complete, remaining, warning, goal_reached,
+# daemon_start and daemon_shutdown. Session types: work, short_break, long_break
+# and any (runs for every type, before the type-specific commands).
+# daemon_start and daemon_shutdown are not about a session and only take any, or a plain list.
+# The original form "hooks.work: [...]" still works and means hooks.complete.work.
+# A hook is a command string, or a map to set options: {command: "...", timeout: 10s}
+# Add stdin: json to a command's map to get the event as a JSON document on stdin.
+# Commands are Go templates rendered with the event, e.g. "echo {{.SessionType}} took {{duration .Elapsed}}".
 hooks:
-  # Triggered when a work session finishes
-  work:
-    # Example for macOS native notification
-    # - "osascript -e 'display notification "Work session complete! Time for a break." with title "Pmdr"'"
-    # Example for Linux native notification (with libnotify)
-    # - "notify-send "Pmdr" "Work session complete! Time for a break."
-  # Triggered when a short break session finishes
-  short_break:
-    # - "osascript -e 'display notification "Break is over! Time for work." with title "Pmdr"'"
-  # Triggered when a long break session finishes
-  long_break:
-    # - "osascript -e 'display notification "Long break is over! Time for work." with title "Pmdr"'"
+  complete:
+    # Triggered when a work session finishes
+    work:
+      # Example for macOS native notification
+      # - "osascript -e 'display notification "Work session complete! Time for a break." with title "Pmdr"'"
+      # Example for Linux native notification (with libnotify)
+      # - "notify-send "Pmdr" "Work session complete! Time for a break.""
+    # Triggered when a short break session finishes
+    short_break:
+      # - "osascript -e 'display notification "Break is over! Time for work." with title "Pmdr"'"
+    # Triggered when a long break session finishes
+    long_break:
+      # - "osascript -e 'display notification "Long break is over! Time for work." with title "Pmdr"'"
+  # Triggered when remaining_before is left in a session
+  remaining:
+    # work:
+    #   - "notify-send "Pmdr" "Five minutes left""
+  # Webhooks send the event as JSON without a shell; method defaults to POST.
+  # stop:
+  #   any:
+  #     - type: webhook
+  #       url: https://example.com/pmdr
+  #       headers: {Authorization: "Bearer TOKEN"}
+  #       body_template: '{"text": "{{.SessionType}} stopped"}'  # Optional; replaces the JSON event
+  #       retries: 2                                              # After network and 5xx errors
+  #       timeout: 10s
+  # Triggered once a day when the daily goal is reached
+  goal_reached:
+    # - "notify-send "Pmdr" "Daily goal reached!""
+  # Triggered when the daemon starts and before it shuts down
+  daemon_start:
+  daemon_shutdown:
+
+# How long before the end of a session the remaining hooks run; 0s disables them
+remaining_before: 0s
+# How long a hook may run before it and its child processes are killed; 0s disables the limit
+hook_timeout: 1m
+# Log level of the output captured from hooks (debug, info, warn or error)
+hook_output_level: info
 `
 
 // InitCmd represents the init command
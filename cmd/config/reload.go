@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// ReloadCmd represents the reload command
+var ReloadCmd = &cobra.Command{
+	Use:   "reload",
+	Short: "Reload the configuration in the running daemon",
+	Long: `Makes the running daemon read the configuration file again. The daemon also reloads
+when the file changes and on SIGHUP. An invalid file is rejected and the current configuration is kept.
+The running session keeps its settings until it ends unless --apply is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		apply, _ := cmd.Flags().GetBool("apply")
+		reply, err := client.Reload(&ipc.ReloadArgs{Apply: apply})
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		reportReload(reply)
+	},
+}
+
+func init() {
+	ReloadCmd.Flags().Bool("apply", false, "Apply the new configuration to the running session too")
+}
+
+// reportReload prints what changed and whether the running session uses the changes.
+func reportReload(reply *ipc.ReloadReply) {
+	if len(reply.Changed) == 0 {
+		slog.Info("Configuration reloaded, nothing changed.")
+		return
+	}
+	slog.Info(fmt.Sprintf("Configuration reloaded, changed: %s.", strings.Join(reply.Changed, ", ")))
+	if reply.Session && !reply.Applied {
+		slog.Info("The running session keeps its settings; use pmdr config reload --apply to apply them now.")
+	}
+}
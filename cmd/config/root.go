@@ -18,5 +18,11 @@ var Cmd = &cobra.Command{
 func Initialize() {
 	Cmd.AddCommand(InitCmd)
 	Cmd.AddCommand(EditCmd)
+	Cmd.AddCommand(ValidateCmd)
+	Cmd.AddCommand(GetCmd)
+	Cmd.AddCommand(SetCmd)
+	Cmd.AddCommand(UnsetCmd)
+	Cmd.AddCommand(ShowCmd)
 	Cmd.AddCommand(StatusCmd)
+	Cmd.AddCommand(ReloadCmd)
 }
@@ -0,0 +1,81 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// SetCmd represents the set command
+var SetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a configuration value",
+	Long: `Set a value in the configuration file, given as a dotted key such as work_duration,
+tts.engine or hooks.work[0]; hooks.work[] appends to the list. The file is edited in place,
+keeping its comments and ordering, and is created if needed. The value is parsed as YAML,
+so lists can be given as [tts, beep]. Changes that add problems to the file are rejected
+without touching it; problems already in the file do not block other changes.`,
+	Example: `  pmdr config set work_duration 50m
+  pmdr config set notify.chain '[desktop, beep]'
+  pmdr config set 'hooks.work[]' 'echo {{shquote .Task}} >> ~/focus.log' --reload`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := configFilePath()
+		if err := config.SetValue(path, args[0], args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		slog.Info(fmt.Sprintf("Set %s in %s", args[0], path))
+		reloadIfRequested(cmd)
+	},
+}
+
+func init() {
+	addReloadFlags(SetCmd)
+}
+
+// configFilePath returns the configuration file in use, or the default one.
+func configFilePath() string {
+	path, err := config.GetConfigFilePath()
+	if err != nil {
+		slog.Error("Failed to get config file path", "error", err)
+		os.Exit(1)
+	}
+	return path
+}
+
+// addReloadFlags adds the flags that make a command reload the daemon after changing the file.
+func addReloadFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("reload", false, "Make the running daemon reload the configuration")
+	cmd.Flags().Bool("apply", false, "With --reload, apply the new configuration to the running session too")
+}
+
+// reloadIfRequested makes the running daemon reload the configuration if --reload is given.
+// Nothing needs to be done when the daemon is not running.
+func reloadIfRequested(cmd *cobra.Command) {
+	if reload, _ := cmd.Flags().GetBool("reload"); !reload {
+		return
+	}
+	conn, err := ipc.Dial()
+	if err != nil {
+		slog.Info("The daemon is not running; the configuration is read when it starts.")
+		return
+	}
+	_ = conn.Close()
+
+	apply, _ := cmd.Flags().GetBool("apply")
+	reply, err := client.Reload(&ipc.ReloadArgs{Apply: apply})
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+	reportReload(reply)
+}
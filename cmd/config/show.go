@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+// ShowCmd represents the show command
+var ShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show the effective configuration",
+	Long: `Show the configuration pmdr uses after merging the defaults, the configuration file,
+environment variables and flags. Each value is annotated with where it comes from: default,
+the path of the config file, env NAME or flag --name. Maps and lists, such as hooks, are
+annotated as a whole.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+
+		cfg, err := config.Load()
+		if err != nil {
+			slog.Error("Failed to load config", "error", err)
+			os.Exit(1)
+		}
+		settings := config.Effective(cfg)
+
+		var out []byte
+		switch format {
+		case "yaml":
+			out, err = config.SettingsYAML(settings)
+		case "json":
+			out, err = config.SettingsJSON(settings)
+		default:
+			err = fmt.Errorf("unknown format %q (expected yaml or json)", format)
+		}
+		if err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		fmt.Print(string(out))
+	},
+}
+
+func init() {
+	ShowCmd.Flags().String("format", "yaml", "Output format (yaml or json)")
+}
@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+// UnsetCmd represents the unset command
+var UnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration value",
+	Long: `Remove a setting from the configuration file so that it takes its default again.
+The key is dotted like for pmdr config set; hooks.work[0] removes the first work hook.
+The file is edited in place, keeping its comments and ordering.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		path := configFilePath()
+		removed, err := config.UnsetValue(path, args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if !removed {
+			slog.Info(fmt.Sprintf("%s is not set in %s", args[0], path))
+			return
+		}
+		slog.Info(fmt.Sprintf("Removed %s from %s", args[0], path))
+		reloadIfRequested(cmd)
+	},
+}
+
+func init() {
+	addReloadFlags(UnsetCmd)
+}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+// ValidateCmd represents the validate command
+var ValidateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check the configuration file for errors",
+	Long: `Check the configuration file for YAML syntax errors, unknown keys and invalid values.
+Each problem is reported with its line and column. Without an argument, the current
+configuration file is checked.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile := viper.ConfigFileUsed()
+		if len(args) > 0 {
+			configFile = args[0]
+		}
+		if configFile == "" {
+			slog.Error("No config file found")
+			os.Exit(1)
+		}
+
+		if err := config.Validate(configFile); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s is valid\n", configFile)
+	},
+}
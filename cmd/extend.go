@@ -0,0 +1,41 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// ExtendCmd represents the extend command
+var ExtendCmd = &cobra.Command{
+	Use:   "extend <duration>",
+	Short: "Extends or shortens the current session",
+	Long: `Moves the end of the current session by the given duration (e.g., 5m).
+Use a negative duration after "--" to shorten the session (e.g., pmdr extend -- -5m).
+The change is limited by extend.max_duration and extend.min_remaining in the config.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid duration: %w", err)
+		}
+
+		reply, err := client.Extend(&ipc.ExtendArgs{Duration: d})
+		if err != nil {
+			return fmt.Errorf("failed to extend session: %w", err)
+		}
+
+		if reply.Applied != d {
+			slog.Warn(fmt.Sprintf("Limited to %s by the configured bounds.", reply.Applied))
+		}
+		slog.Info(fmt.Sprintf("Session now ends at %s (%s remaining).", reply.EndTime.Format("15:04:05"), reply.RemainingTime.Round(time.Second)))
+		return nil
+	},
+}
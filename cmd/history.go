@@ -0,0 +1,102 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/display"
+	"github.com/tsuperis3112/pmdr/internal/history"
+)
+
+// HistoryCmd represents the history command
+var HistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Shows the recorded sessions",
+	Long: `Shows the work and break sessions recorded by the daemon, oldest first.
+
+--since and --until accept a date (2006-01-02), a date and time (2006-01-02 15:04 or RFC 3339),
+or a duration relative to now (e.g. 2h, 7d).`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter, err := historyFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, err := history.DefaultPath()
+		if err != nil {
+			return fmt.Errorf("failed to get history path: %w", err)
+		}
+		entries, err := history.NewJournal(path).Read(filter)
+		if err != nil {
+			return err
+		}
+
+		display.History(cmd.OutOrStdout(), entries)
+		return nil
+	},
+}
+
+func init() {
+	HistoryCmd.Flags().String("since", "", "Show sessions started at or after this time")
+	HistoryCmd.Flags().String("until", "", "Show sessions started before this time")
+	HistoryCmd.Flags().IntP("limit", "n", 0, "Show only the most recent sessions")
+}
+
+// historyFilter builds a history filter from the --since, --until and --limit flags.
+func historyFilter(cmd *cobra.Command) (history.Filter, error) {
+	var filter history.Filter
+	now := time.Now()
+
+	if cmd.Flags().Changed("since") {
+		val, _ := cmd.Flags().GetString("since")
+		t, err := parseTime(val, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+		filter.Since = t
+	}
+	if cmd.Flags().Changed("until") {
+		val, _ := cmd.Flags().GetString("until")
+		t, err := parseTime(val, now)
+		if err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+		filter.Until = t
+	}
+	if cmd.Flags().Lookup("limit") != nil {
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return filter, fmt.Errorf("invalid limit: %d", limit)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// parseTime parses an absolute date or time, or a duration before now.
+// Durations accept a "d" suffix for whole days in addition to time.ParseDuration units.
+func parseTime(val string, now time.Time) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, val, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	if days, ok := strings.CutSuffix(val, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(val); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%q is neither a date, a time nor a duration", val)
+}
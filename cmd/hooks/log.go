@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/display"
+	"github.com/tsuperis3112/pmdr/internal/hook"
+)
+
+// LogCmd represents the log command
+var LogCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show recent hook executions",
+	Long: `Show the hook commands run by the daemon, oldest first,
+with their exit code, duration and, for failures, the error output.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		limit, _ := cmd.Flags().GetInt("limit")
+		if limit < 0 {
+			return fmt.Errorf("invalid limit: %d", limit)
+		}
+		failed, _ := cmd.Flags().GetBool("failed")
+
+		path, err := hook.DefaultLogPath()
+		if err != nil {
+			return fmt.Errorf("failed to get hook log path: %w", err)
+		}
+		records, err := hook.NewLog(path).Read(limit, failed)
+		if err != nil {
+			return err
+		}
+
+		display.HookLog(cmd.OutOrStdout(), records)
+		return nil
+	},
+}
+
+func init() {
+	LogCmd.Flags().IntP("limit", "n", 20, "Show only the most recent executions; 0 shows all")
+	LogCmd.Flags().Bool("failed", false, "Show only failed executions")
+}
@@ -0,0 +1,20 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package hooks
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the hooks command
+var Cmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Inspect hook executions",
+	Long:  `Inspect the hook commands run by the daemon.`,
+}
+
+// Initialize sets up the hooks command and its subcommands.
+func Initialize() {
+	Cmd.AddCommand(LogCmd)
+}
@@ -0,0 +1,44 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// MuteCmd represents the mute command
+var MuteCmd = &cobra.Command{
+	Use:   "mute",
+	Short: "Silences sounds and speech",
+	Long: `Silences spoken notifications, beeps and alert sounds until "pmdr unmute",
+or for the duration given with --for (e.g., pmdr mute --for 1h).
+Hooks still run and silent notifications such as files and webhooks are still delivered.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		d, _ := cmd.Flags().GetDuration("for")
+		if d < 0 {
+			slog.Error(fmt.Sprintf("invalid duration %s", d))
+			os.Exit(1)
+		}
+		if err := client.Mute(&ipc.MuteArgs{Duration: d}); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		if d > 0 {
+			slog.Info(fmt.Sprintf("Muted until %s.", time.Now().Add(d).Format("15:04:05")))
+			return
+		}
+		slog.Info("Muted until pmdr unmute.")
+	},
+}
+
+func init() {
+	MuteCmd.Flags().Duration("for", 0, "Unmute automatically after this duration (e.g., 30m)")
+}
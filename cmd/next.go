@@ -0,0 +1,28 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+)
+
+// NextCmd represents the next command
+var NextCmd = &cobra.Command{
+	Use:     "next",
+	Aliases: []string{"continue"},
+	Short:   "Starts the next session after a finished one",
+	Long: `Starts the next session when the previous one has finished and is waiting
+for a manual transition (see manual_transition in the config).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.Next(); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("Next session started.")
+	},
+}
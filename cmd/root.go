@@ -6,6 +6,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/tsuperis3112/pmdr/cmd/config"
+	"github.com/tsuperis3112/pmdr/cmd/hooks"
+	"github.com/tsuperis3112/pmdr/cmd/task"
 	configInternal "github.com/tsuperis3112/pmdr/internal/config"
 	"github.com/tsuperis3112/pmdr/internal/logging"
 )
@@ -43,14 +45,26 @@ func init() {
 
 	// Initialize sub-packages
 	config.Initialize()
+	hooks.Initialize()
+	task.Initialize()
 
 	// Add subcommands
 	RootCmd.AddCommand(StartCmd)
 	RootCmd.AddCommand(StatusCmd)
+	RootCmd.AddCommand(WatchCmd)
 	RootCmd.AddCommand(PauseCmd)
 	RootCmd.AddCommand(ResumeCmd)
+	RootCmd.AddCommand(SkipCmd)
+	RootCmd.AddCommand(NextCmd)
+	RootCmd.AddCommand(ExtendCmd)
+	RootCmd.AddCommand(MuteCmd)
+	RootCmd.AddCommand(UnmuteCmd)
 	RootCmd.AddCommand(StopCmd)
+	RootCmd.AddCommand(HistoryCmd)
+	RootCmd.AddCommand(StatsCmd)
 	RootCmd.AddCommand(config.Cmd)
+	RootCmd.AddCommand(hooks.Cmd)
+	RootCmd.AddCommand(task.Cmd)
 
 	// Persistent flags
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.config/pmdr/config.yaml)")
@@ -58,9 +72,8 @@ func init() {
 	RootCmd.PersistentFlags().StringVar(&logPath, "log-path", "", "log file path (default is stderr)")
 
 	// Viper binding
-	vip := viper.GetViper()
-	_ = vip.BindPFlag("log.level", RootCmd.PersistentFlags().Lookup("log-level"))
-	_ = vip.BindPFlag("log.path", RootCmd.PersistentFlags().Lookup("log-path"))
+	_ = configInternal.BindFlag("log.level", RootCmd.PersistentFlags().Lookup("log-level"))
+	_ = configInternal.BindFlag("log.path", RootCmd.PersistentFlags().Lookup("log-path"))
 }
 
 func initConfig() {
@@ -0,0 +1,33 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// SkipCmd represents the skip command
+var SkipCmd = &cobra.Command{
+	Use:   "skip",
+	Short: "Skips the current session",
+	Long: `Ends the current session immediately and starts the next one, as if it had run to completion.
+The completion hooks of the skipped session run unless --no-hooks is given.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		noHooks, _ := cmd.Flags().GetBool("no-hooks")
+		if err := client.Skip(&ipc.SkipArgs{RunHooks: !noHooks}); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("Pomodoro session skipped.")
+	},
+}
+
+func init() {
+	SkipCmd.Flags().Bool("no-hooks", false, "Do not run the completion hooks of the skipped session")
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/config"
 	"github.com/tsuperis3112/pmdr/internal/ipc"
 )
 
@@ -24,6 +25,11 @@ var StartCmd = &cobra.Command{
 		// Check if daemon is running
 		_, err := client.Status()
 		if err != nil {
+			// The daemon would exit on an invalid config with its errors in its log; show them here instead.
+			if err := config.ValidateUsed(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
 			slog.Info("Daemon not running, starting it now...")
 			// Assume error means daemon is not running. Attempt to start it.
 			daemonArgs := []string{"daemon"}
@@ -91,6 +97,10 @@ var StartCmd = &cobra.Command{
 			val, _ := cmd.Flags().GetInt("cycles")
 			startArgs.PomoCycles = &val
 		}
+		startArgs.Profile, _ = cmd.Flags().GetString("profile")
+		startArgs.Sequence, _ = cmd.Flags().GetString("sequence")
+		startArgs.Task, _ = cmd.Flags().GetString("task")
+		startArgs.Tags, _ = cmd.Flags().GetStringSlice("tag")
 
 		if err := client.Start(startArgs); err != nil {
 			return fmt.Errorf("failed to start session: %w", err)
@@ -108,4 +118,8 @@ func init() {
 	StartCmd.Flags().StringP("short-break", "s", "", "Short break duration (e.g., 5m)")
 	StartCmd.Flags().StringP("long-break", "l", "", "Long break duration (e.g., 15m)")
 	StartCmd.Flags().IntP("cycles", "c", 0, "Number of work cycles before a long break")
+	StartCmd.Flags().StringP("profile", "p", "", "Profile from the config to use (default is default_profile)")
+	StartCmd.Flags().String("sequence", "", "Sequence from the config to run instead of the pomodoro cycle")
+	StartCmd.Flags().StringP("task", "t", "", "Task the sessions are attributed to")
+	StartCmd.Flags().StringSlice("tag", nil, "Tag for the sessions (repeatable or comma-separated)")
 }
@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/display"
+	"github.com/tsuperis3112/pmdr/internal/history"
+	"github.com/tsuperis3112/pmdr/internal/stats"
+)
+
+// StatsCmd represents the stats command
+var StatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Shows productivity statistics",
+	Long: `Shows completed pomodoros, focused time, break time, average session length
+and completion rate from the session history, grouped by day, ISO week or month.
+
+--since and --until accept the same values as the history command.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, _ := cmd.Flags().GetString("by")
+		period, err := stats.ParsePeriod(by)
+		if err != nil {
+			return err
+		}
+		format, _ := cmd.Flags().GetString("format")
+
+		filter, err := historyFilter(cmd)
+		if err != nil {
+			return err
+		}
+
+		path, err := history.DefaultPath()
+		if err != nil {
+			return fmt.Errorf("failed to get history path: %w", err)
+		}
+		entries, err := history.NewJournal(path).Read(filter)
+		if err != nil {
+			return err
+		}
+
+		return display.Stats(cmd.OutOrStdout(), stats.Compute(entries, period), format)
+	},
+}
+
+func init() {
+	StatsCmd.Flags().StringP("by", "b", string(stats.ByDay), "Group sessions by day, week or month")
+	StatsCmd.Flags().StringP("format", "f", "table", "Output format (table, json, csv)")
+	StatsCmd.Flags().String("since", "", "Include sessions started at or after this time")
+	StatsCmd.Flags().String("until", "", "Include sessions started before this time")
+}
@@ -0,0 +1,27 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package task
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// ClearCmd represents the clear command
+var ClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear the task of the current session",
+	Long:  `Remove the task name and tags from the current and following sessions.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := client.SetTask(&ipc.TaskArgs{}); err != nil {
+			return fmt.Errorf("failed to clear task: %w", err)
+		}
+		slog.Info("Task cleared.")
+		return nil
+	},
+}
@@ -0,0 +1,21 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package task
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd represents the task command
+var Cmd = &cobra.Command{
+	Use:   "task",
+	Short: "Manage the task of the current session",
+	Long:  `Manage the task name and tags that sessions are attributed to.`,
+}
+
+// Initialize sets up the task command and its subcommands.
+func Initialize() {
+	Cmd.AddCommand(SetCmd)
+	Cmd.AddCommand(ClearCmd)
+}
@@ -0,0 +1,34 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package task
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// SetCmd represents the set command
+var SetCmd = &cobra.Command{
+	Use:   "set <task>",
+	Short: "Set the task of the current session",
+	Long: `Set the task name and tags of the current session.
+They replace the previous ones and also apply to the following sessions.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		if err := client.SetTask(&ipc.TaskArgs{Task: args[0], Tags: tags}); err != nil {
+			return fmt.Errorf("failed to set task: %w", err)
+		}
+		slog.Info(fmt.Sprintf("Task set to %s.", args[0]))
+		return nil
+	},
+}
+
+func init() {
+	SetCmd.Flags().StringSlice("tag", nil, "Tag for the sessions (repeatable or comma-separated)")
+}
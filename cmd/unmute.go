@@ -0,0 +1,26 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+)
+
+// UnmuteCmd represents the unmute command
+var UnmuteCmd = &cobra.Command{
+	Use:   "unmute",
+	Short: "Ends a mute",
+	Long:  `Makes notifications audible again after "pmdr mute". Quiet hours still apply.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := client.Unmute(); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		slog.Info("Unmuted.")
+	},
+}
@@ -0,0 +1,30 @@
+/*
+Copyright © 2025 Takeru Furuse
+*/
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/tsuperis3112/pmdr/internal/client"
+	"github.com/tsuperis3112/pmdr/internal/display"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// WatchCmd represents the watch command
+var WatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Streams timer events as they happen",
+	Long: `Keeps a connection to the daemon open and prints every timer event as it happens
+(started, completed, paused, resumed, stopped, skipped, config_reloaded, ...), each with the full status.
+The first line describes the current status. Use --format json for status bars and editors.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, _ := cmd.Flags().GetString("format")
+		return client.Watch(func(event *ipc.Event) error {
+			return display.Event(cmd.OutOrStdout(), event, format)
+		})
+	},
+}
+
+func init() {
+	WatchCmd.Flags().StringP("format", "f", "text", "Output format (text, json)")
+}
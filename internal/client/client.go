@@ -1,7 +1,10 @@
 package client
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/rpc"
 	"os"
@@ -49,6 +52,44 @@ func Resume() error {
 	return call(ipc.ServiceName+".Resume", &ipc.Args{}, &struct{}{})
 }
 
+func Skip(args *ipc.SkipArgs) error {
+	return call(ipc.ServiceName+".Skip", args, &struct{}{})
+}
+
+func SetTask(args *ipc.TaskArgs) error {
+	return call(ipc.ServiceName+".SetTask", args, &struct{}{})
+}
+
+func Next() error {
+	return call(ipc.ServiceName+".Next", &ipc.Args{}, &struct{}{})
+}
+
+func Extend(args *ipc.ExtendArgs) (*ipc.ExtendReply, error) {
+	var reply ipc.ExtendReply
+	err := call(ipc.ServiceName+".Extend", args, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
+func Mute(args *ipc.MuteArgs) error {
+	return call(ipc.ServiceName+".Mute", args, &struct{}{})
+}
+
+func Unmute() error {
+	return call(ipc.ServiceName+".Unmute", &ipc.Args{}, &struct{}{})
+}
+
+func Reload(args *ipc.ReloadArgs) (*ipc.ReloadReply, error) {
+	var reply ipc.ReloadReply
+	err := call(ipc.ServiceName+".Reload", args, &reply)
+	if err != nil {
+		return nil, err
+	}
+	return &reply, nil
+}
+
 func Stop() error {
 	// First, try to gracefully stop the timer via RPC.
 	_ = call(ipc.ServiceName+".Stop", &ipc.Args{}, &struct{}{})
@@ -86,6 +127,34 @@ func Stop() error {
 	return nil
 }
 
+// Watch streams timer events to fn until the daemon goes away or fn returns an error.
+// The first event carries the current status.
+func Watch(fn func(*ipc.Event) error) error {
+	conn, err := ipc.DialWatch()
+	if err != nil {
+		return fmt.Errorf("failed to connect to daemon: %w. Is the daemon running?", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Error("Failed to close watch connection", "error", err)
+		}
+	}()
+
+	dec := json.NewDecoder(conn)
+	for {
+		var event ipc.Event
+		if err := dec.Decode(&event); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("failed to read event: %w", err)
+		}
+		if err := fn(&event); err != nil {
+			return err
+		}
+	}
+}
+
 func Status() (*ipc.StatusReply, error) {
 	var reply ipc.StatusReply
 	err := call(ipc.ServiceName+".Status", &ipc.Args{}, &reply)
@@ -1,9 +1,15 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
@@ -33,39 +39,381 @@ type Config struct {
 	LongBreakDuration  time.Duration `mapstructure:"long_break_duration"`
 	PomoCycles         int           `mapstructure:"pomo_cycles"`
 	Hooks              Hook          `mapstructure:"hooks"`
+	RemainingBefore    time.Duration `mapstructure:"remaining_before"`  // When the remaining hooks run; 0 disables them
+	HookTimeout        time.Duration `mapstructure:"hook_timeout"`      // How long a hook may run before it is killed; 0 disables the limit
+	HookOutputLevel    slog.Level    `mapstructure:"hook_output_level"` // Log level of the output captured from hooks
+	Warnings           Warnings      `mapstructure:"warnings"`
+	Desktop            Desktop       `mapstructure:"desktop"`
+	Extend             Extend        `mapstructure:"extend"`
+	ManualTransition   Manual        `mapstructure:"manual_transition"`
+	DailyGoal          Goal          `mapstructure:"daily_goal"`
+
+	// Notifiers defines notification backends that Notify routes events to.
+	Notifiers map[string]Notifier `mapstructure:"notifiers"`
+	Notify    Notify              `mapstructure:"notify"`
+	TTS       TTS                 `mapstructure:"tts"`
+
+	// QuietHours silences sounds and speech every day, e.g. from 22:00 to 07:00.
+	QuietHours QuietHours `mapstructure:"quiet_hours"`
+
+	// Sequences replace the work/short break/long break cycle with custom ordered steps.
+	Sequences map[string]Sequence `mapstructure:"sequences"`
+	Sequence  string              `mapstructure:"sequence"` // Sequence used by default; empty uses the pomodoro cycle
+
+	// Profiles holds named sets of settings that override the ones above.
+	Profiles       map[string]map[string]any `mapstructure:"profiles" json:"-"`
+	DefaultProfile string                    `mapstructure:"default_profile" json:"-"`
+
+	settings map[string]any // Raw settings the config was decoded from, used to apply profiles
+}
+
+// Sequence is an ordered list of sessions run one after another.
+type Sequence struct {
+	Loop  bool   `mapstructure:"loop"` // Start over after the last step instead of stopping
+	Steps []Step `mapstructure:"steps"`
+}
+
+// Step is a single session in a sequence.
+type Step struct {
+	Name     string        `mapstructure:"name"`
+	Type     string        `mapstructure:"type"` // work, short_break or long_break
+	Duration time.Duration `mapstructure:"duration"`
+	Hooks    []HookCommand `mapstructure:"hooks"` // Run when the step completes, after the hooks of its type
+}
+
+// validate checks that the sequence can be run.
+func (s Sequence) validate(name string) error {
+	if len(s.Steps) == 0 {
+		return fmt.Errorf("sequence %q has no steps", name)
+	}
+	var errs []error
+	for i, step := range s.Steps {
+		switch step.Type {
+		case "work", "short_break", "long_break":
+		default:
+			errs = append(errs, fmt.Errorf("sequence %q step %d: unknown type %q (expected work, short_break or long_break)", name, i+1, step.Type))
+		}
+		if step.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("sequence %q step %d: duration must be positive", name, i+1))
+		}
+		for j, c := range step.Hooks {
+			if err := c.validate(); err != nil {
+				errs = append(errs, fmt.Errorf("sequence %q step %d hooks[%d]: %w", name, i+1, j, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Label returns the name of the step, falling back to its type.
+func (s Step) Label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Type
+}
+
+// Goal is the amount of work to do each day. A zero value disables the corresponding target.
+// When both targets are set, the goal is reached once both are met.
+type Goal struct {
+	Pomodoros int           `mapstructure:"pomodoros"` // Completed work sessions
+	Focus     time.Duration `mapstructure:"focus"`     // Time spent in work sessions, excluding pauses
+}
+
+// Enabled reports whether any target is set.
+func (g Goal) Enabled() bool {
+	return g.Pomodoros > 0 || g.Focus > 0
+}
+
+// Reached reports whether the given progress meets every target that is set.
+func (g Goal) Reached(pomodoros int, focus time.Duration) bool {
+	return g.Enabled() && pomodoros >= g.Pomodoros && focus >= g.Focus
+}
+
+// Manual selects the session types that wait for "pmdr next" when they end
+// instead of rolling into the next session. Unset session types fall back to Default.
+type Manual struct {
+	Default    bool  `mapstructure:"default"`
+	Work       *bool `mapstructure:"work"`
+	ShortBreak *bool `mapstructure:"short_break"`
+	LongBreak  *bool `mapstructure:"long_break"`
 }
 
-// Hook represents a single hook command
-type Hook struct {
-	Work       []string `mapstructure:"work"`
-	ShortBreak []string `mapstructure:"short_break"`
-	LongBreak  []string `mapstructure:"long_break"`
+// For reports whether sessions of the given type (e.g. "short_break") wait for a manual transition.
+func (m Manual) For(sessionType string) bool {
+	var v *bool
+	switch sessionType {
+	case "work":
+		v = m.Work
+	case "short_break":
+		v = m.ShortBreak
+	case "long_break":
+		v = m.LongBreak
+	}
+	if v == nil {
+		return m.Default
+	}
+	return *v
+}
+
+// Warnings selects how long before the end of a session a warning is given, per session type.
+// Unset session types fall back to Default; an empty list disables warnings for the type.
+type Warnings struct {
+	Default    []time.Duration `mapstructure:"default"`
+	Work       []time.Duration `mapstructure:"work"`
+	ShortBreak []time.Duration `mapstructure:"short_break"`
+	LongBreak  []time.Duration `mapstructure:"long_break"`
+}
+
+// For returns the warning thresholds of the given session type (e.g. "short_break").
+func (w Warnings) For(sessionType string) []time.Duration {
+	var v []time.Duration
+	switch sessionType {
+	case "work":
+		v = w.Work
+	case "short_break":
+		v = w.ShortBreak
+	case "long_break":
+		v = w.LongBreak
+	}
+	if v == nil {
+		return w.Default
+	}
+	return v
+}
+
+// validate checks that every threshold is positive.
+func (w Warnings) validate() error {
+	var errs []error
+	for _, thresholds := range [][]time.Duration{w.Default, w.Work, w.ShortBreak, w.LongBreak} {
+		for _, d := range thresholds {
+			if d <= 0 {
+				errs = append(errs, fmt.Errorf("warnings: %s is not a positive duration", d))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// warningsDecodeHook accepts a plain list of thresholds as the warnings of every session type.
+func warningsDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(Warnings{}) {
+		return data, nil
+	}
+	if list, ok := data.([]any); ok {
+		return map[string]any{"default": list}, nil
+	}
+	return data, nil
+}
+
+// Extend holds the bounds applied when a running session is extended or shortened.
+// A zero value disables the corresponding bound.
+type Extend struct {
+	MaxDuration  time.Duration `mapstructure:"max_duration"`  // Longest a session may become, excluding pauses
+	MinRemaining time.Duration `mapstructure:"min_remaining"` // Shortest time that must remain after shortening
 }
 
 // Load loads the configuration from viper
 func Load() (*Config, error) {
-	// Set default values
 	vip := viper.GetViper()
+	setDefaults(vip)
+
+	config, err := decode(vip.AllSettings())
+	if err != nil {
+		return nil, err
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// setDefaults sets the default value of every setting that has one.
+func setDefaults(vip *viper.Viper) {
 	vip.SetDefault("work_duration", "25m")
 	vip.SetDefault("short_break_duration", "5m")
 	vip.SetDefault("long_break_duration", "15m")
 	vip.SetDefault("pomo_cycles", 4)
+	vip.SetDefault("extend.max_duration", "0s")
+	vip.SetDefault("extend.min_remaining", "0s")
+	vip.SetDefault("manual_transition.default", false)
+	vip.SetDefault("daily_goal.pomodoros", 0)
+	vip.SetDefault("daily_goal.focus", "0s")
+	vip.SetDefault("remaining_before", "0s")
+	vip.SetDefault("hook_timeout", "1m")
+	vip.SetDefault("hook_output_level", "info")
+	vip.SetDefault("desktop.enabled", false)
+	vip.SetDefault("notify.chain", []string{NotifierTTS, NotifierBeep})
+	vip.SetDefault("tts.engine", EngineAuto)
+}
 
-	var config Config
+// validate checks the whole configuration and returns every problem found, joined.
+func (c *Config) validate() error {
+	var errs []error
+	for _, e := range c.checkValues() {
+		errs = append(errs, e)
+	}
+
+	// Catch broken profiles when the config is loaded rather than when they are used.
+	if c.DefaultProfile != "" {
+		if _, ok := c.Profiles[c.DefaultProfile]; !ok {
+			errs = append(errs, FieldError{Key: "default_profile", Message: fmt.Sprintf("profile %q is not defined", c.DefaultProfile)})
+		}
+	}
+	sections := c.validateSections()
+	known := map[FieldError]bool{}
+	for _, e := range leafErrors(sections) {
+		fe := fieldError(e)
+		known[FieldError{Key: fe.Key, Message: fe.Message}] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Profiles)) {
+		profile, err := c.WithProfile(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		// Only report the values the profile sets; the others were checked above.
+		for _, e := range profile.checkValues() {
+			if _, ok := c.Profiles[name][strings.SplitN(e.Key, ".", 2)[0]]; ok {
+				e.Key = "profiles." + name + "." + e.Key
+				errs = append(errs, e)
+			}
+		}
+		// Problems the profile shares with the base settings are only reported once, for the base.
+		for _, e := range leafErrors(profile.validateSections()) {
+			fe := fieldError(e)
+			if !known[FieldError{Key: fe.Key, Message: fe.Message}] {
+				fe.Key = strings.TrimSuffix("profiles."+name+"."+fe.Key, ".")
+				errs = append(errs, fe)
+			}
+		}
+	}
+	errs = append(errs, sections)
+	return errors.Join(errs...)
+}
+
+// validateSections checks the hooks, notifications, speech and sequences and returns every problem found, joined.
+func (c *Config) validateSections() error {
+	errs := []error{
+		c.Hooks.validate(),
+		c.Warnings.validate(),
+		c.Desktop.validate(),
+		c.validateNotify(),
+		c.TTS.validate(),
+	}
+	for _, name := range slices.Sorted(maps.Keys(c.Sequences)) {
+		errs = append(errs, c.Sequences[name].validate(name))
+	}
+	if c.Sequence != "" {
+		if _, ok := c.Sequences[c.Sequence]; !ok {
+			errs = append(errs, FieldError{Key: "sequence", Message: fmt.Sprintf("sequence %q is not defined", c.Sequence)})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkValues checks the ranges of durations and counts.
+func (c *Config) checkValues() []FieldError {
+	var errs []FieldError
+	positive := []struct {
+		key string
+		d   time.Duration
+	}{
+		{"work_duration", c.WorkDuration},
+		{"short_break_duration", c.ShortBreakDuration},
+		{"long_break_duration", c.LongBreakDuration},
+	}
+	for _, p := range positive {
+		if p.d <= 0 {
+			errs = append(errs, FieldError{Key: p.key, Message: fmt.Sprintf("must be positive, got %s", p.d)})
+		}
+	}
+	if c.PomoCycles <= 0 {
+		errs = append(errs, FieldError{Key: "pomo_cycles", Message: fmt.Sprintf("must be at least 1, got %d", c.PomoCycles)})
+	}
+
+	nonNegative := []struct {
+		key string
+		d   time.Duration
+	}{
+		{"remaining_before", c.RemainingBefore},
+		{"hook_timeout", c.HookTimeout},
+		{"extend.max_duration", c.Extend.MaxDuration},
+		{"extend.min_remaining", c.Extend.MinRemaining},
+		{"daily_goal.focus", c.DailyGoal.Focus},
+	}
+	for _, n := range nonNegative {
+		if n.d < 0 {
+			errs = append(errs, FieldError{Key: n.key, Message: fmt.Sprintf("must not be negative, got %s", n.d)})
+		}
+	}
+	if c.DailyGoal.Pomodoros < 0 {
+		errs = append(errs, FieldError{Key: "daily_goal.pomodoros", Message: fmt.Sprintf("must not be negative, got %d", c.DailyGoal.Pomodoros)})
+	}
+	return errs
+}
 
-	// Add the custom decode hook
-	decodeHook := mapstructure.ComposeDecodeHookFunc(
-		mapstructure.StringToTimeDurationHookFunc(),
-		mapstructure.StringToSliceHookFunc(","),
-	)
+// decode builds a Config from raw settings the same way viper.Unmarshal does.
+func decode(settings map[string]any) (*Config, error) {
+	var config Config
 
-	if err := vip.Unmarshal(&config, viper.DecodeHook(decodeHook)); err != nil {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		// Add the custom decode hook
+		DecodeHook: mapstructure.ComposeDecodeHookFunc(
+			mapstructure.StringToTimeDurationHookFunc(),
+			mapstructure.StringToSliceHookFunc(","),
+			mapstructure.TextUnmarshallerHookFunc(),
+			hookDecodeHook,
+			hookCommandDecodeHook,
+			warningsDecodeHook,
+		),
+		WeaklyTypedInput: true,
+		Result:           &config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := decoder.Decode(settings); err != nil {
 		return nil, err
 	}
 
+	config.settings = settings
 	return &config, nil
 }
 
+// WithProfile returns a copy of the configuration with the named profile applied on top of it.
+// Nested settings such as hooks are merged key by key; lists are replaced as a whole.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", name)
+	}
+
+	config, err := decode(mergeSettings(c.settings, profile))
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile %q: %w", name, err)
+	}
+	return config, nil
+}
+
+// mergeSettings returns a deep copy of base with the values of override applied on top.
+func mergeSettings(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		if src, ok := v.(map[string]any); ok {
+			if dst, ok := merged[k].(map[string]any); ok {
+				merged[k] = mergeSettings(dst, src)
+				continue
+			}
+		}
+		merged[k] = v
+	}
+	return merged
+}
+
 // FindConfigFile finds the configuration file path.
 func FindConfigFile(cfgFile string) (string, error) {
 	if cfgFile != "" {
@@ -120,6 +468,20 @@ func GetDefaultConfigPaths() (string, string, error) {
 	return configDir, configFile, nil
 }
 
+// GetStateDir returns the directory where pmdr keeps its runtime state across restarts.
+// It honors $XDG_STATE_HOME and falls back to ~/.local/state/pmdr.
+func GetStateDir() (string, error) {
+	if stateHome := os.Getenv("XDG_STATE_HOME"); stateHome != "" {
+		return filepath.Join(stateHome, ProjectName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "state", ProjectName), nil
+}
+
 // GetConfigFilePath returns the path to the configuration file that viper is using.
 // If no file is used, it returns the default path.
 func GetConfigFilePath() (string, error) {
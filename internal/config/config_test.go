@@ -0,0 +1,713 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWithProfile(t *testing.T) {
+	cfg, err := decode(map[string]any{
+		"work_duration":        "25m",
+		"short_break_duration": "5m",
+		"long_break_duration":  "15m",
+		"pomo_cycles":          4,
+		"hooks": map[string]any{
+			"work":        []any{"echo work"},
+			"short_break": []any{"echo break"},
+		},
+		"profiles": map[string]any{
+			"deep-work": map[string]any{
+				"work_duration":        "50m",
+				"short_break_duration": "10m",
+				"hooks": map[string]any{
+					"work": []any{"echo deep"},
+				},
+			},
+			"broken": map[string]any{
+				"pomo_cycles": "many",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("profile overrides only the settings it defines", func(t *testing.T) {
+		deep, err := cfg.WithProfile("deep-work")
+		require.NoError(t, err)
+
+		assert.Equal(t, 50*time.Minute, deep.WorkDuration)
+		assert.Equal(t, 10*time.Minute, deep.ShortBreakDuration)
+		assert.Equal(t, 15*time.Minute, deep.LongBreakDuration)
+		assert.Equal(t, 4, deep.PomoCycles)
+		assert.Equal(t, []string{"echo deep"}, commandStrings(deep.Hooks.Commands(EventComplete, "work")))
+		assert.Equal(t, []string{"echo break"}, commandStrings(deep.Hooks.Commands(EventComplete, "short_break")))
+
+		// The base configuration is left untouched.
+		assert.Equal(t, 25*time.Minute, cfg.WorkDuration)
+		assert.Equal(t, []string{"echo work"}, commandStrings(cfg.Hooks.Commands(EventComplete, "work")))
+	})
+
+	t.Run("unknown profile", func(t *testing.T) {
+		_, err := cfg.WithProfile("missing")
+		assert.ErrorContains(t, err, `unknown profile "missing"`)
+	})
+
+	t.Run("invalid profile", func(t *testing.T) {
+		_, err := cfg.WithProfile("broken")
+		assert.ErrorContains(t, err, `invalid profile "broken"`)
+	})
+}
+
+func TestSequenceValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence Sequence
+		err      string
+	}{
+		{
+			name:     "valid",
+			sequence: Sequence{Steps: []Step{{Type: "work", Duration: time.Minute}, {Type: "long_break", Duration: time.Minute}}},
+		},
+		{
+			name:     "no steps",
+			sequence: Sequence{Loop: true},
+			err:      `sequence "day" has no steps`,
+		},
+		{
+			name:     "unknown type",
+			sequence: Sequence{Steps: []Step{{Type: "lunch", Duration: time.Hour}}},
+			err:      `sequence "day" step 1: unknown type "lunch"`,
+		},
+		{
+			name:     "missing duration",
+			sequence: Sequence{Steps: []Step{{Type: "work", Duration: time.Minute}, {Type: "short_break"}}},
+			err:      `sequence "day" step 2: duration must be positive`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sequence.validate("day")
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestHooks(t *testing.T) {
+	t.Run("event and legacy forms", func(t *testing.T) {
+		cfg, err := decode(map[string]any{
+			"hooks": map[string]any{
+				"work":        []any{"echo legacy work"},
+				"short_break": nil,
+				"complete": map[string]any{
+					"work": []any{"echo complete work"},
+					"any":  []any{"echo complete any"},
+				},
+				"pause":        map[string]any{"long_break": []any{"echo pause"}},
+				"goal_reached": []any{"echo goal"},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.ElementsMatch(t, []string{"echo complete any", "echo legacy work", "echo complete work"}, commandStrings(cfg.Hooks.Commands(EventComplete, "work")))
+		assert.Equal(t, []string{"echo complete any"}, commandStrings(cfg.Hooks.Commands(EventComplete, "short_break")))
+		assert.Equal(t, []string{"echo pause"}, commandStrings(cfg.Hooks.Commands(EventPause, "long_break")))
+		assert.Empty(t, commandStrings(cfg.Hooks.Commands(EventPause, "work")))
+		assert.Equal(t, []string{"echo goal"}, commandStrings(cfg.Hooks.Commands(EventGoalReached, "work")))
+		assert.Equal(t, []string{"echo goal"}, commandStrings(cfg.Hooks.Commands(EventGoalReached, AnySession)))
+	})
+
+	t.Run("command options", func(t *testing.T) {
+		cfg, err := decode(map[string]any{
+			"hooks": map[string]any{
+				"stop": map[string]any{
+					"work": []any{
+						"echo plain",
+						map[string]any{"command": "echo slow", "timeout": "5s"},
+						map[string]any{"command": "jq .event", "stdin": "json"},
+					},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []HookCommand{
+			{Command: "echo plain"},
+			{Command: "echo slow", Timeout: 5 * time.Second},
+			{Command: "jq .event", Stdin: HookStdinJSON},
+		}, cfg.Hooks.Commands(EventStop, "work"))
+	})
+
+	t.Run("unknown stdin", func(t *testing.T) {
+		cfg, err := decode(map[string]any{"hooks": map[string]any{"stop": []any{map[string]any{"command": "cat", "stdin": "yaml"}}}})
+		require.NoError(t, err)
+		assert.ErrorContains(t, cfg.Hooks.validate(), `unknown stdin "yaml"`)
+	})
+
+	t.Run("daemon events only take any", func(t *testing.T) {
+		cfg, err := decode(map[string]any{"hooks": map[string]any{
+			"daemon_start":    []any{"echo up"},
+			"daemon_shutdown": map[string]any{"any": []any{"echo down"}, "work": []any{"echo work"}},
+		}})
+		require.NoError(t, err)
+		assert.EqualError(t, cfg.Hooks.validate(), "hooks.daemon_shutdown.work: daemon_shutdown hooks do not run per session type; use hooks.daemon_shutdown.any")
+	})
+
+	t.Run("unknown event", func(t *testing.T) {
+		_, err := decode(map[string]any{"hooks": map[string]any{"finish": []any{"echo"}}})
+		assert.ErrorContains(t, err, `unknown hook event "finish"`)
+	})
+
+	t.Run("unknown session type", func(t *testing.T) {
+		_, err := decode(map[string]any{"hooks": map[string]any{"start": map[string]any{"lunch": []any{"echo"}}}})
+		assert.ErrorContains(t, err, `hooks.start: unknown session type "lunch"`)
+	})
+}
+
+// commandStrings returns the command strings of the hooks.
+func commandStrings(commands []HookCommand) []string {
+	var s []string
+	for _, c := range commands {
+		s = append(s, c.Command)
+	}
+	return s
+}
+
+func TestWebhook(t *testing.T) {
+	cfg, err := decode(map[string]any{
+		"hooks": map[string]any{
+			"complete": map[string]any{
+				"any": []any{
+					map[string]any{"type": "webhook", "url": "http://localhost/a"},
+					map[string]any{"type": "webhook", "url": "http://localhost/b", "retries": 0},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Hooks.validate())
+
+	commands := cfg.Hooks.Commands(EventComplete, AnySession)
+	require.Len(t, commands, 2)
+	assert.Equal(t, DefaultWebhookRetries, commands[0].Retries)
+	assert.Equal(t, 0, commands[1].Retries)
+	assert.Equal(t, "POST http://localhost/a", commands[0].String())
+
+	for name, c := range map[string]HookCommand{
+		"no url":       {Type: HookWebhook},
+		"bad template": {Type: HookWebhook, URL: "http://localhost", BodyTemplate: "{{.Task"},
+		"unknown type": {Type: "email", Command: "x"},
+		"bad command":  {Command: "notify-send {{.SesionType}}"},
+		"no command":   {},
+	} {
+		assert.Error(t, c.validate(), name)
+	}
+}
+
+func TestWarnings(t *testing.T) {
+	t.Run("list applies to every session type", func(t *testing.T) {
+		cfg, err := decode(map[string]any{"warnings": []any{"2m", "30s"}})
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{2 * time.Minute, 30 * time.Second}, cfg.Warnings.For("long_break"))
+	})
+
+	t.Run("per session type", func(t *testing.T) {
+		cfg, err := decode(map[string]any{"warnings": map[string]any{
+			"default": []any{"1m"},
+			"work":    []any{"5m"},
+		}})
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{5 * time.Minute}, cfg.Warnings.For("work"))
+		assert.Equal(t, []time.Duration{time.Minute}, cfg.Warnings.For("short_break"))
+	})
+
+	t.Run("thresholds must be positive", func(t *testing.T) {
+		cfg, err := decode(map[string]any{"warnings": []any{"0s"}})
+		require.NoError(t, err)
+		assert.Error(t, cfg.Warnings.validate())
+	})
+}
+
+func TestDesktop(t *testing.T) {
+	cfg, err := decode(map[string]any{"desktop": map[string]any{
+		"enabled": true,
+		"icon":    "/usr/share/icons/pmdr.png",
+		"events": map[string]any{
+			"complete": map[string]any{"body": "{{.Task}} done", "urgency": "critical"},
+			"pause":    map[string]any{"title": "Paused"},
+			"start":    map[string]any{"disabled": true},
+		},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, cfg.Desktop.validate())
+
+	n, ok := cfg.Desktop.For(EventComplete)
+	require.True(t, ok)
+	assert.Equal(t, defaultNotifications[EventComplete].Title, n.Title)
+	assert.Equal(t, "{{.Task}} done", n.Body)
+	assert.Equal(t, "/usr/share/icons/pmdr.png", n.Icon)
+	assert.Equal(t, UrgencyCritical, n.Urgency)
+
+	n, ok = cfg.Desktop.For(EventPause)
+	require.True(t, ok)
+	assert.Equal(t, Notification{Title: "Paused", Icon: "/usr/share/icons/pmdr.png", Urgency: UrgencyNormal}, n)
+
+	_, ok = cfg.Desktop.For(EventStart)
+	assert.False(t, ok, "disabled")
+	_, ok = cfg.Desktop.For(EventResume)
+	assert.False(t, ok, "no default")
+
+	cfg.Desktop.Enabled = false
+	_, ok = cfg.Desktop.For(EventComplete)
+	assert.False(t, ok, "not enabled")
+
+	for name, d := range map[string]Desktop{
+		"unknown event":   {Events: map[string]Notification{"lunch": {}}},
+		"unknown urgency": {Events: map[string]Notification{EventStart: {Urgency: "high"}}},
+		"bad template":    {Events: map[string]Notification{EventStart: {Body: "{{.Tsk}}"}}},
+	} {
+		assert.Error(t, d.validate(), name)
+	}
+
+	for event, n := range defaultNotifications {
+		assert.NoError(t, Desktop{Events: map[string]Notification{event: n}}.validate(), event)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	cfg, err := decode(map[string]any{
+		"notifiers": map[string]any{
+			"phone": map[string]any{"type": "webhook", "url": "https://ntfy.sh/pmdr", "headers": map[string]any{"Title": "pmdr"}},
+			"tts":   map[string]any{"type": "command", "command": "piper"},
+		},
+		"notify": map[string]any{
+			"chain":  []any{"tts", "beep"},
+			"events": map[string]any{"goal_reached": []any{"phone", "desktop"}, "warning": []any{}},
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, cfg.validateNotify())
+
+	n, ok := cfg.Notifier("tts")
+	require.True(t, ok)
+	assert.Equal(t, Notifier{Type: NotifierCommand, Command: "piper"}, n, "redefined built-in")
+	n, ok = cfg.Notifier("beep")
+	require.True(t, ok)
+	assert.Equal(t, NotifierBeep, n.Type)
+	assert.Equal(t, map[string]string{"Title": "pmdr"}, cfg.Notifiers["phone"].Headers)
+	assert.Equal(t, []string{}, cfg.Notify.Events[EventWarning])
+	_, ok = cfg.Notifier("phone2")
+	assert.False(t, ok)
+
+	for name, c := range map[string]*Config{
+		"unknown type":     {Notifiers: map[string]Notifier{"x": {Type: "pager"}}},
+		"command missing":  {Notifiers: map[string]Notifier{"x": {Type: NotifierCommand}}},
+		"unknown notifier": {Notify: Notify{Chain: []string{"pager"}}},
+		"unknown event":    {Notify: Notify{Events: map[string][]string{"lunch": {"tts"}}}},
+	} {
+		assert.Error(t, c.validateNotify(), name)
+	}
+}
+
+func TestTTS(t *testing.T) {
+	cfg, err := decode(map[string]any{"tts": map[string]any{
+		"engine": "espeak-ng",
+		"voice":  "en-us",
+		"rate":   1.2,
+		"messages": map[string]any{
+			"start": "Go {{.Task}}",
+			"pause": "Paused.",
+			"stop":  "",
+		},
+	}})
+	require.NoError(t, err)
+	require.NoError(t, cfg.TTS.validate())
+	assert.Equal(t, 1.2, cfg.TTS.Rate)
+
+	assert.Equal(t, "Go {{.Task}}", cfg.TTS.Message(EventStart))
+	assert.Equal(t, "Paused.", cfg.TTS.Message(EventPause))
+	assert.Equal(t, "", cfg.TTS.Message(EventStop), "silenced")
+	assert.Equal(t, defaultMessages[EventWarning], cfg.TTS.Message(EventWarning))
+	assert.Equal(t, "", cfg.TTS.Message(EventResume), "no default")
+
+	for name, tts := range map[string]TTS{
+		"unknown engine":   {Engine: "sam"},
+		"piper voice":      {Engine: EnginePiper},
+		"negative rate":    {Rate: -1},
+		"loud":             {Volume: 3},
+		"command template": {Command: []string{"say", "{{.Msg}}"}},
+		"unknown event":    {Messages: map[string]string{"lunch": "Eat."}},
+		"message template": {Messages: map[string]string{EventStart: "{{.Tsk}}"}},
+	} {
+		assert.Error(t, tts.validate(), name)
+	}
+	assert.NoError(t, TTS{Engine: EnginePiper, Command: []string{"piper-say", "{{.Message}}"}}.validate())
+
+	for event, m := range defaultMessages {
+		assert.NoError(t, TTS{Messages: map[string]string{event: m}}.validate(), event)
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	cfg, err := decode(map[string]any{"quiet_hours": map[string]any{"start": "22:00", "end": "7:30"}})
+	require.NoError(t, err)
+	assert.Equal(t, QuietHours{Start: 22 * 60, End: 7*60 + 30}, cfg.QuietHours)
+	assert.Equal(t, "07:30", cfg.QuietHours.End.String())
+
+	at := func(clock string) time.Time {
+		c, err := ParseClock(clock)
+		require.NoError(t, err)
+		return time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local).Add(time.Duration(c) * time.Minute)
+	}
+	assert.True(t, cfg.QuietHours.Contains(at("22:00")))
+	assert.True(t, cfg.QuietHours.Contains(at("03:00")))
+	assert.False(t, cfg.QuietHours.Contains(at("07:30")))
+	assert.False(t, cfg.QuietHours.Contains(at("12:00")))
+
+	day := QuietHours{Start: 12 * 60, End: 13 * 60}
+	assert.True(t, day.Contains(at("12:59")))
+	assert.False(t, day.Contains(at("13:00")))
+	assert.False(t, QuietHours{}.Contains(at("00:00")), "disabled")
+
+	_, err = decode(map[string]any{"quiet_hours": map[string]any{"start": "late"}})
+	assert.Error(t, err)
+}
+
+func TestDiff(t *testing.T) {
+	old, err := decode(map[string]any{
+		"work_duration": "25m",
+		"pomo_cycles":   4,
+		"hooks":         map[string]any{"start": []any{"echo a"}},
+		"tts":           map[string]any{"voice": "en"},
+	})
+	require.NoError(t, err)
+	new, err := decode(map[string]any{
+		"work_duration": "25m",
+		"pomo_cycles":   3,
+		"hooks":         map[string]any{"start": []any{"echo a", "echo b"}},
+		"tts":           map[string]any{"voice": "en", "rate": 1.2},
+		"daily_goal":    map[string]any{"pomodoros": 8},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"daily_goal", "hooks.start", "pomo_cycles", "tts.rate"}, Diff(old, new))
+	assert.Empty(t, Diff(old, old))
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want []string
+	}{
+		{
+			name: "valid",
+			yaml: "work_duration: 50m\npomo_cycles: 2\nhooks:\n  work:\n    - echo work\nlog:\n  level: debug\n",
+		},
+		{
+			name: "empty",
+			yaml: "",
+		},
+		{
+			name: "invalid values",
+			yaml: "work_duration: -5m\npomo_cycles: 0\ntts:\n  rate: -1\n",
+			want: []string{
+				"1:1: work_duration: must be positive, got -5m0s",
+				"2:1: pomo_cycles: must be at least 1, got 0",
+				"4:3: tts.rate: must not be negative, got -1",
+			},
+		},
+		{
+			name: "unknown keys",
+			yaml: "work_duraton: 25m\ndesktop:\n  enabled: true\n  colour: red\n",
+			want: []string{
+				"1:1: work_duraton: unknown key (did you mean work_duration?)",
+				"4:3: desktop.colour: unknown key",
+			},
+		},
+		{
+			name: "profile",
+			yaml: "profiles:\n  deep:\n    work_duration: 0s\n    pomo_cycle: 2\n",
+			want: []string{
+				"3:5: profiles.deep.work_duration: must be positive, got 0s",
+				"4:5: profiles.deep.pomo_cycle: unknown key (did you mean pomo_cycles?)",
+			},
+		},
+		{
+			name: "every hook error",
+			yaml: "hooks:\n  stop:\n    any:\n      - {command: echo, stdin: xml}\n  complete:\n    work:\n      - echo ok\n      - {type: ftp}\n",
+			want: []string{
+				`4:9: hooks.stop.any[0]: unknown stdin "xml" (expected json)`,
+				`8:9: hooks.complete.work[1]: unknown hook type "ftp" (expected command or webhook)`,
+			},
+		},
+		{
+			name: "profile sections",
+			yaml: "profiles:\n  deep:\n    hooks:\n      start:\n        work: \"echo {{.Tsk}}\"\n    notify:\n      chain: [nosuch]\n    tts:\n      engine: bogus\n",
+			want: []string{
+				`5:9: profiles.deep.hooks.start.work[0]: invalid command template: unknown field "Tsk" (expected one of Event, SessionType, Cycle, StartedAt, EndedAt, Duration, Elapsed, Remaining, Step, Task, Tags)`,
+				`7:7: profiles.deep.notify.chain: unknown notifier "nosuch"`,
+				`9:7: profiles.deep.tts.engine: unknown engine "bogus" (expected one of [auto say spd-say espeak-ng piper festival powershell])`,
+			},
+		},
+		{
+			name: "every tts and warnings error",
+			yaml: "warnings: [0s, -1s]\ntts:\n  messages:\n    start: \"{{.Tsk}}\"\n    finish: done\n    complete: \"{{\"\n",
+			want: []string{
+				"1:1: warnings: 0s is not a positive duration",
+				"1:1: warnings: -1s is not a positive duration",
+				`3:3: tts.messages: unknown event "finish"`,
+				`4:5: tts.messages.start: invalid template: unknown field "Tsk" (expected one of Event, SessionType, Cycle, StartedAt, EndedAt, Duration, Elapsed, Remaining, Step, Task, Tags)`,
+				`6:5: tts.messages.complete: invalid template: template: hook:1: unclosed action`,
+			},
+		},
+		{
+			name: "undecodable value",
+			yaml: "pomo_cycles: 4\nlong_break_duration: soon\n",
+			want: []string{`2:1: long_break_duration: time: invalid duration "soon"`},
+		},
+		{
+			name: "undecodable section with other errors",
+			yaml: "work_duration: 0s\nhooks:\n  bogus: [x]\ntts:\n  rate: -1\n",
+			want: []string{
+				"1:1: work_duration: must be positive, got 0s",
+				`2:1: hooks: unknown hook event "bogus"`,
+				"5:3: tts.rate: must not be negative, got -1",
+			},
+		},
+		{
+			name: "syntax error",
+			yaml: "work_duration: 25m\n  pomo_cycles: 4\n",
+			want: []string{"2:1: mapping values are not allowed in this context"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateData("config.yaml", []byte(tt.yaml))
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			var got []string
+			for _, fe := range verr.Errors {
+				got = append(got, fe.Error())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLocate(t *testing.T) {
+	var doc yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte("hooks:\n  work:\n    - echo a\n    - echo b\nTTS:\n  rate: 1\n"), &doc))
+	root := doc.Content[0]
+
+	tests := []struct {
+		key       string
+		line, col int
+	}{
+		{"hooks.work[1]", 4, 7},
+		{"hooks.work", 2, 3},
+		{"tts.rate", 6, 3},
+		{"hooks.break", 1, 1},
+		{"hooks.work[5]", 2, 3},
+		{"sequence", 0, 0},
+	}
+	for _, tt := range tests {
+		line, col := locate(root, tt.key)
+		assert.Equal(t, []int{tt.line, tt.col}, []int{line, col}, tt.key)
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	steps, err := parseKey("Hooks.work[2][]")
+	require.NoError(t, err)
+	assert.Equal(t, []keyStep{{name: "hooks"}, {name: "work"}, {index: 2, isList: true}, {index: -1, isList: true}}, steps)
+
+	for _, key := range []string{"", "hooks..work", "[0]", "hooks.work[a]", "hooks.work[0]x"} {
+		_, err := parseKey(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	const original = `# pmdr configuration file
+work_duration: 25m # focus
+hooks:
+  work:
+    - echo work # first
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(original), 0600))
+
+	require.NoError(t, SetValue(path, "work_duration", "50m"))
+	require.NoError(t, SetValue(path, "hooks.work[]", "echo {{shquote .Task}}"))
+	require.NoError(t, SetValue(path, "hooks.work[0]", "echo focus"))
+	require.NoError(t, SetValue(path, "notify.chain", "[desktop, beep]"))
+	require.NoError(t, SetValue(path, "tts.rate", "1.5"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# pmdr configuration file
+work_duration: 50m # focus
+hooks:
+  work:
+    - echo focus # first
+    - echo {{shquote .Task}}
+notify:
+  chain: [desktop, beep]
+tts:
+  rate: 1.5
+`, string(data))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// Invalid results are not written.
+	var verr *ValidationError
+	require.ErrorAs(t, SetValue(path, "pomo_cycles", "0"), &verr)
+	require.ErrorAs(t, SetValue(path, "work_duraton", "50m"), &verr)
+	assert.ErrorContains(t, SetValue(path, "hooks.work[5]", "echo"), "out of range")
+	assert.ErrorContains(t, SetValue(path, "work_duration.x", "1"), "is not a map")
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(after))
+
+	// A missing file is created.
+	path = filepath.Join(t.TempDir(), "pmdr", "config.yaml")
+	require.NoError(t, SetValue(path, "hooks.start.work[]", "echo start"))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "hooks:\n  start:\n    work:\n      - echo start\n", string(data))
+
+	// A file that is already invalid can be fixed one setting at a time.
+	path = filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("work_duraton: 3m\npomo_cycles: 0\n"), 0644))
+	require.NoError(t, SetValue(path, "pomo_cycles", "4"))
+	require.ErrorAs(t, SetValue(path, "short_break_duration", "-1m"), &verr)
+	assert.Len(t, verr.Errors, 1)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "work_duraton: 3m\npomo_cycles: 4\n", string(data))
+}
+
+func TestUnsetValue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`# pmdr configuration file
+work_duration: 50m
+hooks:
+  work:
+    - echo a
+    - echo b
+tts:
+  rate: 1.5
+`), 0644))
+
+	for _, key := range []string{"hooks.work[0]", "tts.rate", "work_duration"} {
+		removed, err := UnsetValue(path, key)
+		require.NoError(t, err, key)
+		assert.True(t, removed, key)
+	}
+	removed, err := UnsetValue(path, "tts.rate")
+	require.NoError(t, err)
+	assert.False(t, removed)
+	_, err = UnsetValue(path, "hooks.work[]")
+	assert.Error(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# pmdr configuration file\nhooks:\n  work:\n    - echo b\n", string(data))
+
+	// Settings can be removed from a file that is already invalid.
+	require.NoError(t, os.WriteFile(path, []byte("work_duraton: 3m\npomo_cycles: 0\n"), 0644))
+	removed, err = UnsetValue(path, "work_duraton")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	removed, err = UnsetValue(path, "pomo_cycles")
+	require.NoError(t, err)
+	assert.True(t, removed)
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("work_duration: 50m\npomo_cycles: 2\n"), 0644))
+	vip := viper.New()
+	setDefaults(vip)
+	vip.AutomaticEnv()
+	vip.SetConfigFile(path)
+	require.NoError(t, vip.ReadInConfig())
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.Duration("long", 0, "")
+	boundFlags["long_break_duration"] = flags.Lookup("long")
+	t.Cleanup(func() { delete(boundFlags, "long_break_duration") })
+	require.NoError(t, vip.BindPFlag("long_break_duration", flags.Lookup("long")))
+	require.NoError(t, flags.Parse([]string{"--long", "20m"}))
+	t.Setenv("POMO_CYCLES", "3")
+	t.Setenv("TTS", "ignored")
+
+	assert.Equal(t, path, source(vip, "work_duration"))
+	assert.Equal(t, "env POMO_CYCLES", source(vip, "pomo_cycles"))
+	assert.Equal(t, "flag --long", source(vip, "long_break_duration"))
+	assert.Equal(t, SourceDefault, source(vip, "short_break_duration"))
+	assert.Equal(t, SourceDefault, source(vip, "tts"))
+}
+
+func TestSettingsOutput(t *testing.T) {
+	enabled := true
+	var settings []Setting
+	for _, s := range []struct {
+		key    string
+		value  any
+		source string
+	}{
+		{"work_duration", 50 * time.Minute, "/home/me/.config/pmdr/config.yaml"},
+		{"hooks", Hook{EventComplete: {"work": {{Command: "echo done"}}}}, "/home/me/.config/pmdr/config.yaml"},
+		{"manual_transition.work", &enabled, "env MANUAL_TRANSITION.WORK"},
+		{"manual_transition.short_break", (*bool)(nil), SourceDefault},
+		{"quiet_hours.start", Clock(22 * 60), SourceDefault},
+		{"notify.events", map[string][]string{}, SourceDefault},
+	} {
+		settings = append(settings, Setting{Key: s.key, Value: plainValue(reflect.ValueOf(s.value)), Source: s.source})
+	}
+
+	out, err := SettingsYAML(settings)
+	require.NoError(t, err)
+	assert.Equal(t, `work_duration: 50m0s # /home/me/.config/pmdr/config.yaml
+hooks: # /home/me/.config/pmdr/config.yaml
+  complete:
+    work:
+      - command: echo done
+manual_transition:
+  work: true # env MANUAL_TRANSITION.WORK
+  short_break: null # default
+quiet_hours:
+  start: "22:00" # default
+notify:
+  events: {} # default
+`, string(out))
+
+	out, err = SettingsJSON(settings[:2])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"work_duration": {"value": "50m0s", "source": "/home/me/.config/pmdr/config.yaml"},
+		"hooks": {"value": {"complete": {"work": [{"command": "echo done"}]}}, "source": "/home/me/.config/pmdr/config.yaml"}
+	}`, string(out))
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// Hook events.
+const (
+	EventStart          = "start"
+	EventPause          = "pause"
+	EventResume         = "resume"
+	EventStop           = "stop"
+	EventSkip           = "skip"
+	EventComplete       = "complete"
+	EventRemaining      = "remaining" // remaining_before is left in the session
+	EventWarning        = "warning"   // One of the warnings thresholds is reached
+	EventGoalReached    = "goal_reached"
+	EventDaemonStart    = "daemon_start"
+	EventDaemonShutdown = "daemon_shutdown"
+)
+
+// Events lists every hook event.
+var Events = []string{
+	EventStart,
+	EventPause,
+	EventResume,
+	EventStop,
+	EventSkip,
+	EventComplete,
+	EventRemaining,
+	EventWarning,
+	EventGoalReached,
+	EventDaemonStart,
+	EventDaemonShutdown,
+}
+
+// AnySession is the session type key of commands that run for every session type.
+const AnySession = "any"
+
+// SessionTypes lists the session type keys accepted under each hook event.
+var SessionTypes = []string{"work", "short_break", "long_break", AnySession}
+
+// Hook holds the commands run on lifecycle events, keyed as hooks.<event>.<session_type>.
+type Hook map[string]map[string][]HookCommand
+
+// Hook types.
+const (
+	HookShell   = "command" // Runs Command with sh -c
+	HookWebhook = "webhook" // Sends the event to URL
+)
+
+// HookStdinJSON passes the event to a command as a JSON document on stdin.
+const HookStdinJSON = "json"
+
+// DefaultWebhookRetries is the number of times a failed webhook is retried unless set.
+const DefaultWebhookRetries = 2
+
+// HookCommand is a single hook. It is written either as a plain command string
+// or as a map when more options are needed.
+type HookCommand struct {
+	Type    string        `mapstructure:"type"` // command (the default) or webhook
+	Command string        `mapstructure:"command"`
+	Timeout time.Duration `mapstructure:"timeout"` // Overrides hook_timeout for this command
+	Stdin   string        `mapstructure:"stdin"`   // json writes the event to stdin; empty leaves stdin empty
+
+	URL          string            `mapstructure:"url"`
+	Method       string            `mapstructure:"method"` // Defaults to POST
+	Headers      map[string]string `mapstructure:"headers"`
+	BodyTemplate string            `mapstructure:"body_template"` // Replaces the JSON event document
+	Retries      int               `mapstructure:"retries"`       // Retries after network and server errors
+}
+
+// String returns the command, or the method and URL of a webhook.
+func (c HookCommand) String() string {
+	if c.Type != HookWebhook {
+		return c.Command
+	}
+	method := c.Method
+	if method == "" {
+		method = "POST"
+	}
+	return method + " " + c.URL
+}
+
+// validate checks that the hook can be run.
+func (c HookCommand) validate() error {
+	switch c.Type {
+	case "", HookShell:
+		if c.Command == "" {
+			return errors.New("command is empty")
+		}
+		if err := tmpl.Check(c.Command); err != nil {
+			return fmt.Errorf("invalid command template: %w", err)
+		}
+		if c.Stdin != "" && c.Stdin != HookStdinJSON {
+			return fmt.Errorf("unknown stdin %q (expected json)", c.Stdin)
+		}
+	case HookWebhook:
+		if c.URL == "" {
+			return errors.New("webhook has no url")
+		}
+		if err := tmpl.Check(c.BodyTemplate); err != nil {
+			return fmt.Errorf("invalid body template: %w", err)
+		}
+	default:
+		return fmt.Errorf("unknown hook type %q (expected command or webhook)", c.Type)
+	}
+	if c.Retries < 0 {
+		return errors.New("retries must not be negative")
+	}
+	return nil
+}
+
+// validate checks every hook and returns all the problems found, joined, in the order of their keys.
+func (h Hook) validate() error {
+	var errs []error
+	for _, event := range slices.Sorted(maps.Keys(h)) {
+		for _, sessionType := range slices.Sorted(maps.Keys(h[event])) {
+			// The daemon events are not about a session, so only their any commands run.
+			if (event == EventDaemonStart || event == EventDaemonShutdown) && sessionType != AnySession {
+				errs = append(errs, fmt.Errorf("hooks.%s.%s: %s hooks do not run per session type; use hooks.%s.%s", event, sessionType, event, event, AnySession))
+				continue
+			}
+			for i, c := range h[event][sessionType] {
+				if err := c.validate(); err != nil {
+					errs = append(errs, fmt.Errorf("hooks.%s.%s[%d]: %w", event, sessionType, i, err))
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnmarshalJSON accepts the plain command strings of snapshots written by older versions.
+func (c *HookCommand) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &c.Command); err == nil {
+		return nil
+	}
+	type plain HookCommand
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Commands returns the commands for the event and session type,
+// starting with the ones configured for any session type.
+func (h Hook) Commands(event, sessionType string) []HookCommand {
+	byType := h[event]
+	commands := slices.Clone(byType[AnySession])
+	if sessionType != AnySession {
+		commands = append(commands, byType[sessionType]...)
+	}
+	return commands
+}
+
+// hookCommandDecodeHook decodes a plain command string into a HookCommand
+// and fills in the defaults of webhooks.
+func hookCommandDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(HookCommand{}) {
+		return data, nil
+	}
+	switch v := data.(type) {
+	case string:
+		return HookCommand{Command: v}, nil
+	case map[string]any:
+		if _, ok := v["retries"]; !ok && v["type"] == HookWebhook {
+			v = maps.Clone(v)
+			v["retries"] = DefaultWebhookRetries
+		}
+		return v, nil
+	}
+	return data, nil
+}
+
+// hookDecodeHook normalizes the hooks section to hooks.<event>.<session_type> before decoding.
+//
+// Two shorthands are accepted:
+//   - hooks.<session_type>: [...] is the original form and means hooks.complete.<session_type>.
+//   - hooks.<event>: [...] means hooks.<event>.any.
+func hookDecodeHook(from reflect.Type, to reflect.Type, data any) (any, error) {
+	if to != reflect.TypeOf(Hook{}) {
+		return data, nil
+	}
+	raw, ok := data.(map[string]any)
+	if !ok {
+		return data, nil
+	}
+
+	normalized := make(map[string]map[string]any)
+	add := func(event, sessionType string, commands any) {
+		if commands == nil {
+			return
+		}
+		if normalized[event] == nil {
+			normalized[event] = make(map[string]any)
+		}
+		if existing, ok := normalized[event][sessionType].([]any); ok {
+			if more, ok := commands.([]any); ok {
+				commands = append(slices.Clone(existing), more...)
+			}
+		}
+		normalized[event][sessionType] = commands
+	}
+
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		byType, isMap := value.(map[string]any)
+
+		switch {
+		case !isMap && slices.Contains(SessionTypes, key) && key != AnySession:
+			add(EventComplete, key, value)
+		case !slices.Contains(Events, key):
+			return nil, fmt.Errorf("unknown hook event %q", key)
+		case !isMap:
+			add(key, AnySession, value)
+		default:
+			for sessionType, commands := range byType {
+				if !slices.Contains(SessionTypes, sessionType) {
+					return nil, fmt.Errorf("hooks.%s: unknown session type %q", key, sessionType)
+				}
+				add(key, sessionType, commands)
+			}
+		}
+	}
+	return normalized, nil
+}
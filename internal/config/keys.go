@@ -0,0 +1,329 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// keyStep is one step of a dotted key: a setting name, or an index into a list.
+type keyStep struct {
+	name   string
+	index  int  // -1 appends to the list
+	isList bool // The step is an index rather than a name
+}
+
+// keyIndex matches the list indexes of a key segment, e.g. "[0]" or "[]".
+var keyIndex = regexp.MustCompile(`\[(\d*)\]`)
+
+// parseKey splits a dotted key such as "hooks.work[0]" into its steps.
+// An empty index, as in "hooks.work[]", stands for the end of the list.
+func parseKey(key string) ([]keyStep, error) {
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+	var steps []keyStep
+	for _, segment := range strings.Split(key, ".") {
+		name, rest, _ := strings.Cut(segment, "[")
+		if rest != "" {
+			rest = "[" + rest
+		}
+		if name == "" || keyIndex.ReplaceAllString(rest, "") != "" {
+			return nil, fmt.Errorf("invalid key %q", key)
+		}
+		steps = append(steps, keyStep{name: strings.ToLower(name)})
+		for _, m := range keyIndex.FindAllStringSubmatch(rest, -1) {
+			index := -1
+			if m[1] != "" {
+				index, _ = strconv.Atoi(m[1])
+			}
+			steps = append(steps, keyStep{index: index, isList: true})
+		}
+	}
+	return steps, nil
+}
+
+// Get returns the effective value of the setting with the dotted key,
+// from the configuration file, the environment or the defaults.
+// It reports false when the setting is not set.
+func Get(key string) (any, bool, error) {
+	steps, err := parseKey(key)
+	if err != nil {
+		return nil, false, err
+	}
+	vip := viper.GetViper()
+	setDefaults(vip)
+	var value any = vip.AllSettings()
+	for _, step := range steps {
+		if m, ok := value.(map[string]any); ok && !step.isList {
+			if value, ok = m[step.name]; !ok {
+				return nil, false, nil
+			}
+			continue
+		}
+		// Lists from the defaults are typed, such as []string.
+		list := reflect.ValueOf(value)
+		if !step.isList || list.Kind() != reflect.Slice || step.index < 0 || step.index >= list.Len() {
+			return nil, false, nil
+		}
+		value = list.Index(step.index).Interface()
+	}
+	return value, true, nil
+}
+
+// SetValue sets the setting with the dotted key in the configuration file at path,
+// keeping its comments and the order of the other settings. The value is parsed as YAML,
+// so lists and maps can be given in flow style such as "[tts, beep]"; values that are not
+// valid YAML, and templates such as "{{.Task}}", are taken as strings.
+// The file is created if it does not exist, and it is not written if the result is invalid.
+func SetValue(path, key, value string) error {
+	steps, err := parseKey(key)
+	if err != nil {
+		return err
+	}
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+	if err := setNode(doc.Content[0], steps, parseValue(value), key); err != nil {
+		return err
+	}
+	return writeDocument(path, doc)
+}
+
+// UnsetValue removes the setting with the dotted key from the configuration file at path,
+// so that it takes its default again. Settings left empty by the removal are removed too.
+// It reports false, without writing the file, when the setting is not in the file.
+func UnsetValue(path, key string) (bool, error) {
+	steps, err := parseKey(key)
+	if err != nil {
+		return false, err
+	}
+	for _, step := range steps {
+		if step.isList && step.index < 0 {
+			return false, fmt.Errorf("invalid key %q: an index is required", key)
+		}
+	}
+	doc, err := readDocument(path)
+	if err != nil {
+		return false, err
+	}
+	if !unsetNode(doc.Content[0], steps) {
+		return false, nil
+	}
+	return true, writeDocument(path, doc)
+}
+
+// parseValue converts a value given on the command line to a YAML node.
+func parseValue(value string) *yaml.Node {
+	str := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if strings.HasPrefix(strings.TrimSpace(value), "{{") {
+		return str
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil || len(doc.Content) == 0 {
+		return str
+	}
+	return doc.Content[0]
+}
+
+// setNode sets the value at the steps below n, creating the mappings and lists on the way.
+func setNode(n *yaml.Node, steps []keyStep, value *yaml.Node, key string) error {
+	step := steps[0]
+	last := len(steps) == 1
+
+	var child *yaml.Node
+	if step.isList {
+		if n.Kind != yaml.SequenceNode {
+			return fmt.Errorf("%s: %s is not a list", key, nodeKind(n))
+		}
+		switch {
+		case step.index < 0 || step.index == len(n.Content):
+			n.Content = append(n.Content, newNode(steps[1:], value))
+			return nil
+		case step.index < len(n.Content):
+			child = n.Content[step.index]
+			if last {
+				n.Content[step.index] = replaceNode(child, value)
+			}
+		default:
+			return fmt.Errorf("%s: index %d is out of range (the list has %d items)", key, step.index, len(n.Content))
+		}
+	} else {
+		if n.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s: %s is not a map", key, nodeKind(n))
+		}
+		switch i := entryIndex(n, step.name); {
+		case i < 0:
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: step.name}, newNode(steps[1:], value))
+			return nil
+		case last:
+			n.Content[i] = replaceNode(n.Content[i], value)
+		default:
+			child = n.Content[i]
+		}
+	}
+
+	if last {
+		return nil
+	}
+	// An empty setting, as in "hooks:", is filled in.
+	if child.Kind == yaml.ScalarNode && child.Tag == "!!null" {
+		*child = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if steps[1].isList {
+			*child = yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		}
+	}
+	return setNode(child, steps[1:], value, key)
+}
+
+// newNode builds the node that holds the value at the steps.
+func newNode(steps []keyStep, value *yaml.Node) *yaml.Node {
+	if len(steps) == 0 {
+		return value
+	}
+	if steps[0].isList {
+		return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{newNode(steps[1:], value)}}
+	}
+	return &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Tag: "!!str", Value: steps[0].name},
+		newNode(steps[1:], value),
+	}}
+}
+
+// replaceNode returns the new value, keeping the comments of the old one.
+func replaceNode(old, value *yaml.Node) *yaml.Node {
+	value.HeadComment = old.HeadComment
+	value.LineComment = old.LineComment
+	value.FootComment = old.FootComment
+	return value
+}
+
+// unsetNode removes the value at the steps below n, and reports whether it was there.
+func unsetNode(n *yaml.Node, steps []keyStep) bool {
+	step := steps[0]
+	var i int // Index of the child in n.Content
+	if step.isList {
+		if n.Kind != yaml.SequenceNode || step.index >= len(n.Content) {
+			return false
+		}
+		i = step.index
+	} else {
+		if i = entryIndex(n, step.name); i < 0 {
+			return false
+		}
+	}
+
+	child := n.Content[i]
+	if len(steps) > 1 {
+		if !unsetNode(child, steps[1:]) {
+			return false
+		}
+		if len(child.Content) > 0 {
+			return true
+		}
+	}
+	if step.isList {
+		n.Content = append(n.Content[:i], n.Content[i+1:]...)
+	} else {
+		// A comment above the first key usually heads the whole map, such as the file header; keep it.
+		if i == 1 && len(n.Content) > 2 && n.Content[2].HeadComment == "" {
+			n.Content[2].HeadComment = n.Content[0].HeadComment
+		}
+		n.Content = append(n.Content[:i-1], n.Content[i+1:]...)
+	}
+	return true
+}
+
+// entryIndex returns the index in n.Content of the value of a mapping entry, or -1.
+// Keys are matched case-insensitively like viper.
+func entryIndex(n *yaml.Node, key string) int {
+	if n.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if strings.EqualFold(n.Content[i].Value, key) {
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// nodeKind describes the kind of a node for error messages.
+func nodeKind(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "a map"
+	case yaml.SequenceNode:
+		return "a list"
+	}
+	return fmt.Sprintf("%q", n.Value)
+}
+
+// readDocument reads the configuration file as a YAML document with a map at its root.
+// A missing or empty file gives an empty map.
+func readDocument(path string) (*yaml.Node, error) {
+	doc := &yaml.Node{Kind: yaml.DocumentNode}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return nil, &ValidationError{Path: path, Errors: []FieldError{syntaxError(err)}}
+	}
+	if len(doc.Content) == 0 {
+		doc.Kind = yaml.DocumentNode
+		doc.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s does not hold a map of settings", path)
+	}
+	return doc, nil
+}
+
+// writeDocument validates the document and writes it to the configuration file.
+// Only problems the edit adds are refused, so that a broken file can be fixed one setting at a time.
+func writeDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %w", err)
+	}
+	data := buf.Bytes()
+	if len(doc.Content[0].Content) == 0 {
+		data = nil // Rather than "{}"
+	}
+
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := addedErrors(validateData(path, before), validateData(path, data)); err != nil {
+		return err
+	}
+
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, mode); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// Notification urgencies.
+const (
+	UrgencyLow      = "low"
+	UrgencyNormal   = "normal"
+	UrgencyCritical = "critical"
+)
+
+// Desktop configures native desktop notifications.
+type Desktop struct {
+	Enabled bool                    `mapstructure:"enabled"`
+	Icon    string                  `mapstructure:"icon"` // Used by notifications that do not set their own
+	Events  map[string]Notification `mapstructure:"events"`
+}
+
+// Notification is the desktop notification shown for an event.
+// Title and body are templates rendered like hook commands.
+type Notification struct {
+	Title    string `mapstructure:"title"`
+	Body     string `mapstructure:"body"`
+	Icon     string `mapstructure:"icon"`
+	Urgency  string `mapstructure:"urgency"` // low, normal (the default) or critical
+	Disabled bool   `mapstructure:"disabled"`
+}
+
+// defaultNotifications are shown when desktop notifications are enabled,
+// unless the event is configured otherwise.
+var defaultNotifications = map[string]Notification{
+	EventStart: {
+		Title: "{{human .SessionType}} started",
+		Body:  "{{duration .Duration}}{{with .Task}} · {{.}}{{end}}",
+	},
+	EventComplete: {
+		Title: "{{human .SessionType}} complete",
+		Body:  "Done after {{duration .Elapsed}}{{with .Task}} · {{.}}{{end}}",
+	},
+	EventWarning: {
+		Title: "{{duration .Remaining}} remaining",
+		Body:  "{{human .SessionType}}{{with .Task}} · {{.}}{{end}}",
+	},
+	EventGoalReached: {
+		Title:   "Daily goal reached",
+		Body:    "Well done.",
+		Urgency: UrgencyCritical,
+	},
+}
+
+// For returns the notification for the event, with the fields that are not configured
+// taken from the default notification. It reports false when nothing is shown for the event.
+func (d Desktop) For(event string) (Notification, bool) {
+	if !d.Enabled {
+		return Notification{}, false
+	}
+
+	n, ok := defaultNotifications[event]
+	if custom, found := d.Events[event]; found {
+		ok = true
+		if custom.Title != "" {
+			n.Title = custom.Title
+		}
+		if custom.Body != "" {
+			n.Body = custom.Body
+		}
+		if custom.Icon != "" {
+			n.Icon = custom.Icon
+		}
+		if custom.Urgency != "" {
+			n.Urgency = custom.Urgency
+		}
+		n.Disabled = custom.Disabled
+	}
+	if !ok || n.Disabled {
+		return Notification{}, false
+	}
+
+	if n.Title == "" {
+		n.Title = ProjectName
+	}
+	if n.Icon == "" {
+		n.Icon = d.Icon
+	}
+	if n.Urgency == "" {
+		n.Urgency = UrgencyNormal
+	}
+	return n, true
+}
+
+// validate checks the events, urgencies and templates of the notifications.
+func (d Desktop) validate() error {
+	var errs []error
+	for _, event := range slices.Sorted(maps.Keys(d.Events)) {
+		n := d.Events[event]
+		if !slices.Contains(Events, event) {
+			errs = append(errs, fmt.Errorf("desktop.events: unknown event %q", event))
+			continue
+		}
+		switch n.Urgency {
+		case "", UrgencyLow, UrgencyNormal, UrgencyCritical:
+		default:
+			errs = append(errs, fmt.Errorf("desktop.events.%s: unknown urgency %q (expected low, normal or critical)", event, n.Urgency))
+		}
+		if err := tmpl.Check(n.Title); err != nil {
+			errs = append(errs, fmt.Errorf("desktop.events.%s: invalid title template: %w", event, err))
+		}
+		if err := tmpl.Check(n.Body); err != nil {
+			errs = append(errs, fmt.Errorf("desktop.events.%s: invalid body template: %w", event, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Notifier backend types.
+const (
+	NotifierTTS     = "tts"     // Speaks the message with the OS's text-to-speech engine
+	NotifierBeep    = "beep"    // Plays a beep
+	NotifierDesktop = "desktop" // Shows a desktop notification
+	NotifierCommand = "command" // Runs a command with the message in $PMDR_MESSAGE
+	NotifierFile    = "file"    // Appends the message to a file
+	NotifierWebhook = "webhook" // Posts the message and the event as JSON
+)
+
+// builtinNotifiers can be used in chains without being defined under notifiers.
+var builtinNotifiers = []string{NotifierTTS, NotifierBeep, NotifierDesktop}
+
+// Notifier defines a notification backend.
+type Notifier struct {
+	Type    string            `mapstructure:"type"`
+	Command string            `mapstructure:"command"` // command
+	Path    string            `mapstructure:"path"`    // file
+	URL     string            `mapstructure:"url"`     // webhook
+	Headers map[string]string `mapstructure:"headers"` // webhook
+}
+
+// validate checks that the backend can be used.
+func (n Notifier) validate() error {
+	switch n.Type {
+	case NotifierTTS, NotifierBeep, NotifierDesktop:
+	case NotifierCommand:
+		if n.Command == "" {
+			return errors.New("command is empty")
+		}
+	case NotifierFile:
+		if n.Path == "" {
+			return errors.New("file has no path")
+		}
+	case NotifierWebhook:
+		if n.URL == "" {
+			return errors.New("webhook has no url")
+		}
+	default:
+		return fmt.Errorf("unknown type %q (expected tts, beep, desktop, command, file or webhook)", n.Type)
+	}
+	return nil
+}
+
+// Notify routes the notifications of each event to a chain of backends.
+// The backends of a chain are tried in order until one of them succeeds.
+type Notify struct {
+	Chain  []string            `mapstructure:"chain"`  // Used by events without a chain of their own
+	Events map[string][]string `mapstructure:"events"` // An empty chain silences the event
+}
+
+// Notifier returns the backend with the given name.
+// The built-in tts, beep and desktop backends can be redefined under notifiers.
+func (c *Config) Notifier(name string) (Notifier, bool) {
+	if n, ok := c.Notifiers[name]; ok {
+		return n, true
+	}
+	if slices.Contains(builtinNotifiers, name) {
+		return Notifier{Type: name}, true
+	}
+	return Notifier{}, false
+}
+
+// validateNotify checks the backends and that every chain only refers to known ones.
+func (c *Config) validateNotify() error {
+	var errs []error
+	for _, name := range slices.Sorted(maps.Keys(c.Notifiers)) {
+		if err := c.Notifiers[name].validate(); err != nil {
+			errs = append(errs, fmt.Errorf("notifiers.%s: %w", name, err))
+		}
+	}
+
+	chains := map[string][]string{"notify.chain": c.Notify.Chain}
+	for _, event := range slices.Sorted(maps.Keys(c.Notify.Events)) {
+		chain := c.Notify.Events[event]
+		if !slices.Contains(Events, event) {
+			errs = append(errs, fmt.Errorf("notify.events: unknown event %q", event))
+			continue
+		}
+		chains["notify.events."+event] = chain
+	}
+	for _, key := range slices.Sorted(maps.Keys(chains)) {
+		for _, name := range chains[key] {
+			if _, ok := c.Notifier(name); !ok {
+				errs = append(errs, fmt.Errorf("%s: unknown notifier %q", key, name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Clock is a time of day in minutes after midnight, written as "15:04".
+type Clock int
+
+// ParseClock parses a time of day such as "22:00" or "7:30".
+func ParseClock(s string) (Clock, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", s)
+	}
+	return Clock(t.Hour()*60 + t.Minute()), nil
+}
+
+// String returns the time of day in 15:04 form.
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", int(c)/60, int(c)%60)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Clock) UnmarshalText(text []byte) error {
+	parsed, err := ParseClock(string(text))
+	if err != nil {
+		return err
+	}
+	*c = parsed
+	return nil
+}
+
+// QuietHours is a daily time range in which notifications make no sound.
+// The range may wrap around midnight; equal start and end disable it.
+type QuietHours struct {
+	Start Clock `mapstructure:"start"`
+	End   Clock `mapstructure:"end"`
+}
+
+// Contains reports whether t falls in the quiet hours, in t's location.
+func (q QuietHours) Contains(t time.Time) bool {
+	c := Clock(t.Hour()*60 + t.Minute())
+	switch {
+	case q.Start == q.End:
+		return false
+	case q.Start < q.End:
+		return c >= q.Start && c < q.End
+	default:
+		return c >= q.Start || c < q.End
+	}
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+
+	"github.com/spf13/viper"
+)
+
+// Reload reads the configuration file again and loads it.
+// A file created since the daemon started is picked up as well.
+func Reload() (*Config, error) {
+	vip := viper.GetViper()
+	if vip.ConfigFileUsed() == "" {
+		path, err := FindConfigFile("")
+		if err != nil {
+			return nil, err
+		}
+		if path != "" {
+			vip.SetConfigFile(path)
+		}
+	}
+	if vip.ConfigFileUsed() != "" {
+		if err := ValidateUsed(); err != nil {
+			return nil, err
+		}
+		if err := vip.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	return Load()
+}
+
+// Diff returns the dotted keys of the settings that differ between two loaded configurations, sorted.
+// Lists are compared as a whole.
+func Diff(old, new *Config) []string {
+	var keys []string
+	diffSettings("", old.settings, new.settings, &keys)
+	slices.Sort(keys)
+	return keys
+}
+
+// diffSettings appends the keys under prefix whose values differ between a and b.
+func diffSettings(prefix string, a, b map[string]any, keys *[]string) {
+	seen := make(map[string]bool, len(a)+len(b))
+	for _, m := range []map[string]any{a, b} {
+		for k := range m {
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+
+			key := prefix + k
+			subA, okA := a[k].(map[string]any)
+			subB, okB := b[k].(map[string]any)
+			if okA && okB {
+				diffSettings(key+".", subA, subB, keys)
+				continue
+			}
+			if !reflect.DeepEqual(a[k], b[k]) {
+				*keys = append(*keys, key)
+			}
+		}
+	}
+}
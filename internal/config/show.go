@@ -0,0 +1,207 @@
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// Sources of settings other than the configuration file, whose source is its path.
+const (
+	SourceDefault = "default"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// boundFlags maps settings to the command-line flags bound to them.
+var boundFlags = map[string]*pflag.Flag{}
+
+// BindFlag binds a setting to a command-line flag. A flag that is set takes precedence
+// over the environment and the configuration file.
+func BindFlag(key string, flag *pflag.Flag) error {
+	boundFlags[key] = flag
+	return viper.BindPFlag(key, flag)
+}
+
+// Setting is a resolved setting and where its value comes from.
+type Setting struct {
+	Key    string // Dotted key, e.g. "tts.engine"
+	Value  any    // Plain value: a string, number, bool, list or map
+	Source string // "default", the path of the config file, "env NAME" or "flag --name"
+}
+
+// Effective lists the settings of the loaded configuration with their sources, in the order
+// of the configuration. Maps and lists, such as hooks, are a single setting.
+func Effective(cfg *Config) []Setting {
+	vip := viper.GetViper()
+	var settings []Setting
+	collectSettings(vip, "", reflect.ValueOf(*cfg), &settings)
+	collectSettings(vip, "log", reflect.ValueOf(logSettings{
+		Level: vip.GetString("log.level"),
+		Path:  vip.GetString("log.path"),
+	}), &settings)
+	return settings
+}
+
+// collectSettings appends the fields of a settings struct, recursing into nested structs.
+func collectSettings(vip *viper.Viper, prefix string, v reflect.Value, settings *[]Setting) {
+	t := v.Type()
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "" || !f.IsExported() {
+			continue
+		}
+		key := joinKey(prefix, name)
+		if f.Type.Kind() == reflect.Struct && !isText(f.Type) {
+			collectSettings(vip, key, v.Field(i), settings)
+			continue
+		}
+		*settings = append(*settings, Setting{Key: key, Value: plainValue(v.Field(i)), Source: source(vip, key)})
+	}
+}
+
+// source returns where viper takes the setting from, in viper's order of precedence.
+func source(vip *viper.Viper, key string) string {
+	if f, ok := boundFlags[key]; ok && f.Changed {
+		return SourceFlag + " --" + f.Name
+	}
+	// Environment variables are only looked up for the settings viper knows.
+	env := strings.ToUpper(key)
+	if os.Getenv(env) != "" && slices.Contains(vip.AllKeys(), key) {
+		return SourceEnv + " " + env
+	}
+	if vip.InConfig(key) {
+		return vip.ConfigFileUsed()
+	}
+	return SourceDefault
+}
+
+// isText reports whether values of the type are written as text, such as times of day.
+func isText(t reflect.Type) bool {
+	return t.Implements(reflect.TypeFor[encoding.TextMarshaler]())
+}
+
+// plainValue converts a setting to the form it is written in: durations and other text values
+// become strings, and structs become maps without their empty fields.
+func plainValue(v reflect.Value) any {
+	switch {
+	case !v.IsValid():
+		return nil
+	case v.Type() == reflect.TypeFor[time.Duration]():
+		return v.Interface().(time.Duration).String()
+	case isText(v.Type()):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(text)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return plainValue(v.Elem())
+	case reflect.Struct:
+		m := map[string]any{}
+		for i := range v.NumField() {
+			f := v.Type().Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+			if name == "" || !f.IsExported() || v.Field(i).IsZero() {
+				continue
+			}
+			m[name] = plainValue(v.Field(i))
+		}
+		return m
+	case reflect.Map:
+		m := make(map[string]any, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			m[fmt.Sprint(it.Key().Interface())] = plainValue(it.Value())
+		}
+		return m
+	case reflect.Slice:
+		list := make([]any, v.Len())
+		for i := range list {
+			list[i] = plainValue(v.Index(i))
+		}
+		return list
+	}
+	return v.Interface()
+}
+
+// SettingsYAML writes the settings as a YAML document, with the source of each one in a comment.
+func SettingsYAML(settings []Setting) ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, s := range settings {
+		parent, names := root, strings.Split(s.Key, ".")
+		for _, name := range names[:len(names)-1] {
+			i := entryIndex(parent, name)
+			if i < 0 {
+				parent.Content = append(parent.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name},
+					&yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+				i = len(parent.Content) - 1
+			}
+			parent = parent.Content[i]
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: names[len(names)-1]}
+		value := &yaml.Node{}
+		if err := value.Encode(s.Value); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", s.Key, err)
+		}
+		if value.Kind == yaml.ScalarNode || len(value.Content) == 0 {
+			value.LineComment = s.Source
+		} else {
+			key.LineComment = s.Source
+		}
+		parent.Content = append(parent.Content, key, value)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SettingsJSON writes the settings as a JSON document, where each setting is an object
+// holding its value and its source.
+func SettingsJSON(settings []Setting) ([]byte, error) {
+	root := map[string]any{}
+	for _, s := range settings {
+		parent, names := root, strings.Split(s.Key, ".")
+		for _, name := range names[:len(names)-1] {
+			child, ok := parent[name].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[name] = child
+			}
+			parent = child
+		}
+		parent[names[len(names)-1]] = map[string]any{"value": s.Value, "source": s.Source}
+	}
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode settings: %w", err)
+	}
+	return append(data, '\n'), nil
+}
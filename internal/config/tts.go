@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// Text-to-speech engines.
+const (
+	EngineAuto       = "auto"       // say on macOS, spd-say on Linux and PowerShell on Windows
+	EngineSay        = "say"        // macOS
+	EngineSpdSay     = "spd-say"    // Speech Dispatcher
+	EngineEspeak     = "espeak-ng"  // eSpeak NG
+	EnginePiper      = "piper"      // Piper; the voice is the path of the model
+	EngineFestival   = "festival"   // Festival
+	EnginePowerShell = "powershell" // System.Speech on Windows
+)
+
+// Engines lists the supported text-to-speech engines.
+var Engines = []string{EngineAuto, EngineSay, EngineSpdSay, EngineEspeak, EnginePiper, EngineFestival, EnginePowerShell}
+
+// TTS configures the text-to-speech backend and what it says about each event.
+type TTS struct {
+	Engine  string   `mapstructure:"engine"`
+	Command []string `mapstructure:"command"` // Custom argv used instead of the engine; "{{.Message}}" is replaced with the text
+	Voice   string   `mapstructure:"voice"`
+	Rate    float64  `mapstructure:"rate"`   // Speed relative to the engine's default, e.g. 1.2; 0 keeps the default
+	Volume  float64  `mapstructure:"volume"` // Volume relative to the engine's default, up to 2; 0 keeps the default
+	// Messages are templates rendered like hook commands. An empty message silences the event.
+	Messages map[string]string `mapstructure:"messages"`
+}
+
+// defaultMessages are spoken unless the event is configured otherwise.
+// complete is only spoken when the timer waits for "pmdr next", and stop only when a sequence ends.
+var defaultMessages = map[string]string{
+	EventStart:       `{{if eq .SessionType "work"}}Work session started.{{else}}Time for a {{lower (human .SessionType)}}.{{end}}`,
+	EventWarning:     "{{spell .Remaining}} remaining.",
+	EventComplete:    "Session complete.",
+	EventStop:        "Session complete.",
+	EventGoalReached: "Daily goal reached. Well done.",
+}
+
+// Message returns the template of the message spoken for the event.
+func (t TTS) Message(event string) string {
+	if m, ok := t.Messages[event]; ok {
+		return m
+	}
+	return defaultMessages[event]
+}
+
+// CommandData is what the arguments of a custom command are rendered with.
+type CommandData struct {
+	Message string
+	Voice   string
+	Rate    float64
+	Volume  float64
+}
+
+// validate checks the engine, the options and the templates.
+func (t TTS) validate() error {
+	var errs []error
+	if t.Engine != "" && !slices.Contains(Engines, t.Engine) {
+		errs = append(errs, fmt.Errorf("tts.engine: unknown engine %q (expected one of %v)", t.Engine, Engines))
+	}
+	if t.Engine == EnginePiper && t.Voice == "" && len(t.Command) == 0 {
+		errs = append(errs, fmt.Errorf("tts.voice: piper needs the path of a voice model"))
+	}
+	if t.Rate < 0 {
+		errs = append(errs, fmt.Errorf("tts.rate: must not be negative, got %v", t.Rate))
+	}
+	if t.Volume < 0 || t.Volume > 2 {
+		errs = append(errs, fmt.Errorf("tts.volume: must be between 0 and 2, got %v", t.Volume))
+	}
+	for i, arg := range t.Command {
+		if _, err := tmpl.Render(arg, CommandData{}); err != nil {
+			errs = append(errs, fmt.Errorf("tts.command[%d]: invalid template: %w", i, err))
+		}
+	}
+	for _, event := range slices.Sorted(maps.Keys(t.Messages)) {
+		if !slices.Contains(Events, event) {
+			errs = append(errs, fmt.Errorf("tts.messages: unknown event %q", event))
+			continue
+		}
+		if err := tmpl.Check(t.Messages[event]); err != nil {
+			errs = append(errs, fmt.Errorf("tts.messages.%s: invalid template: %w", event, err))
+		}
+	}
+	return errors.Join(errs...)
+}
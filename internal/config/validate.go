@@ -0,0 +1,379 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// FieldError is a problem with a single setting.
+type FieldError struct {
+	Key     string // Dotted key, e.g. "hooks.start.work[0]"; empty when the problem is not about one setting
+	Line    int    // Position of the setting in the file; 0 when it is not known
+	Column  int
+	Message string
+}
+
+// Error implements error.
+func (e FieldError) Error() string {
+	msg := e.Message
+	if e.Key != "" {
+		msg = e.Key + ": " + msg
+	}
+	if e.Line > 0 {
+		msg = fmt.Sprintf("%d:%d: %s", e.Line, e.Column, msg)
+	}
+	return msg
+}
+
+// ValidationError lists every problem found in a configuration file, in file order.
+type ValidationError struct {
+	Path   string
+	Errors []FieldError
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "invalid config file %s:", e.Path)
+	for _, fe := range e.Errors {
+		sb.WriteString("\n  ")
+		sb.WriteString(fe.Error())
+	}
+	return sb.String()
+}
+
+// Validate checks the configuration file at path: its YAML syntax, unknown keys
+// and every value, as Load would see them with the defaults applied.
+// Problems are reported as a *ValidationError with the line and column of each setting.
+func Validate(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+	return validateData(path, data)
+}
+
+// ValidateUsed validates the configuration file in use, if any.
+func ValidateUsed() error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return nil
+	}
+	return Validate(path)
+}
+
+// validateData checks the contents of a configuration file.
+func validateData(path string, data []byte) error {
+	verr := &ValidationError{Path: path}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		verr.Errors = append(verr.Errors, syntaxError(err))
+		return verr
+	}
+	var root *yaml.Node
+	if len(doc.Content) > 0 {
+		root = doc.Content[0]
+	}
+	if root != nil {
+		checkKeys(root, reflect.TypeFor[Config](), "", &verr.Errors)
+	}
+
+	// Decode the file with the defaults only, so the environment does not hide problems in it.
+	vip := viper.New()
+	setDefaults(vip)
+	vip.SetConfigType(DefaultConfigType)
+	if err := vip.ReadConfig(bytes.NewReader(data)); err != nil {
+		verr.Errors = append(verr.Errors, FieldError{Message: err.Error()})
+		return verr
+	}
+	cfg, err := decodeSections(vip.AllSettings())
+	if cfg != nil {
+		err = errors.Join(err, cfg.validate())
+	}
+	for _, e := range leafErrors(err) {
+		fe := fieldError(e)
+		fe.Line, fe.Column = locate(root, fe.Key)
+		verr.Errors = append(verr.Errors, fe)
+	}
+
+	if len(verr.Errors) == 0 {
+		return nil
+	}
+	slices.SortStableFunc(verr.Errors, func(a, b FieldError) int {
+		if a.Line != b.Line {
+			return a.Line - b.Line
+		}
+		return a.Column - b.Column
+	})
+	return verr
+}
+
+// decodeSections decodes the settings one top-level setting at a time, so that a setting that
+// cannot be decoded does not keep the others from being checked. Such settings take their defaults.
+func decodeSections(settings map[string]any) (*Config, error) {
+	if cfg, err := decode(settings); err == nil {
+		return cfg, nil
+	}
+	defaults := viper.New()
+	setDefaults(defaults)
+	decoded := map[string]any{}
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(settings)) {
+		if _, err := decode(map[string]any{key: settings[key]}); err != nil {
+			errs = append(errs, err)
+			if value := defaults.Get(key); value != nil {
+				decoded[key] = value
+			}
+			continue
+		}
+		decoded[key] = settings[key]
+	}
+	cfg, err := decode(decoded)
+	if err != nil {
+		return nil, err
+	}
+	return cfg, errors.Join(errs...)
+}
+
+// addedErrors returns the problems of after that are not in before, or nil if there are none.
+// Problems are compared by key and message, since an edit can move settings to other lines.
+func addedErrors(before, after error) error {
+	var verr *ValidationError
+	if !errors.As(after, &verr) {
+		return after
+	}
+	known := map[FieldError]int{}
+	var old *ValidationError
+	if errors.As(before, &old) {
+		for _, fe := range old.Errors {
+			known[FieldError{Key: fe.Key, Message: fe.Message}]++
+		}
+	}
+	added := &ValidationError{Path: verr.Path}
+	for _, fe := range verr.Errors {
+		if k := (FieldError{Key: fe.Key, Message: fe.Message}); known[k] > 0 {
+			known[k]--
+			continue
+		}
+		added.Errors = append(added.Errors, fe)
+	}
+	if len(added.Errors) == 0 {
+		return nil
+	}
+	return added
+}
+
+// yamlLine matches the line number in YAML syntax errors.
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): `)
+
+// syntaxError converts a YAML syntax error to a FieldError.
+func syntaxError(err error) FieldError {
+	msg := err.Error()
+	if m := yamlLine.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return FieldError{Line: line, Column: 1, Message: strings.TrimPrefix(msg, m[0])}
+	}
+	return FieldError{Message: msg}
+}
+
+// leafErrors flattens joined and wrapped errors, such as those of validate and of the decoder.
+func leafErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var leaves []error
+		for _, e := range joined.Unwrap() {
+			leaves = append(leaves, leafErrors(e)...)
+		}
+		return leaves
+	}
+	if inner := errors.Unwrap(err); inner != nil && strings.HasPrefix(err.Error(), "decoding failed") {
+		return leafErrors(inner)
+	}
+	return []error{err}
+}
+
+var (
+	// keyPrefix matches errors that start with the key they are about, e.g. "tts.rate: ...".
+	keyPrefix = regexp.MustCompile(`^([a-z_][a-z0-9_.\[\]-]*): `)
+	// quotedKey matches the key in decoder errors, e.g. "'work_duration' time: invalid duration".
+	quotedKey = regexp.MustCompile(`^(?:error decoding )?'([^']*)':? `)
+	// mapIndex matches the map keys in decoder names, e.g. "[work]" in "hooks[work]".
+	mapIndex = regexp.MustCompile(`\[([^\]0-9][^\]]*)\]`)
+)
+
+// fieldError finds the key an error is about.
+func fieldError(err error) FieldError {
+	var fe FieldError
+	if errors.As(err, &fe) {
+		return fe
+	}
+	msg := err.Error()
+	if m := quotedKey.FindStringSubmatch(msg); m != nil {
+		return FieldError{Key: mapIndex.ReplaceAllString(m[1], ".$1"), Message: strings.TrimPrefix(msg, m[0])}
+	}
+	if m := keyPrefix.FindStringSubmatch(msg); m != nil {
+		return FieldError{Key: m[1], Message: strings.TrimPrefix(msg, m[0])}
+	}
+	return FieldError{Message: msg}
+}
+
+// logSettings are read by the commands rather than the daemon, so they are not part of Config.
+type logSettings struct {
+	Level string `mapstructure:"level"`
+	Path  string `mapstructure:"path"`
+}
+
+// checkKeys reports keys of the node that the type t has no setting for.
+// Nodes in another form than t, such as a plain list of hooks, are left to the decode hooks.
+func checkKeys(n *yaml.Node, t reflect.Type, key string, errs *[]FieldError) {
+	switch t.Kind() {
+	case reflect.Struct:
+		if n.Kind != yaml.MappingNode || t == reflect.TypeFor[time.Time]() {
+			return
+		}
+		fields := settingFields(t)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			name := strings.ToLower(k.Value)
+			ft, ok := fields[name]
+			if !ok {
+				*errs = append(*errs, FieldError{Key: joinKey(key, k.Value), Line: k.Line, Column: k.Column, Message: unknownKey(name, fields)})
+				continue
+			}
+			checkKeys(v, ft, joinKey(key, k.Value), errs)
+		}
+	case reflect.Map:
+		if n.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			checkKeys(n.Content[i+1], t.Elem(), joinKey(key, n.Content[i].Value), errs)
+		}
+	case reflect.Slice:
+		if n.Kind != yaml.SequenceNode {
+			return
+		}
+		for i, v := range n.Content {
+			checkKeys(v, t.Elem(), fmt.Sprintf("%s[%d]", key, i), errs)
+		}
+	}
+}
+
+// settingFields maps the setting names of a struct to the types of their fields.
+func settingFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("mapstructure"), ",")
+		if name == "" || !f.IsExported() {
+			continue
+		}
+		fields[name] = f.Type
+	}
+	if t == reflect.TypeFor[Config]() {
+		// Profiles hold settings of their own.
+		fields["profiles"] = reflect.TypeFor[map[string]Config]()
+		fields["log"] = reflect.TypeFor[logSettings]()
+	}
+	return fields
+}
+
+// unknownKey describes an unknown key, suggesting the closest known one.
+func unknownKey(name string, fields map[string]reflect.Type) string {
+	best, bestDist := "", 3
+	for known := range fields {
+		if d := editDistance(name, known); d < bestDist || (d == bestDist && known < best) {
+			best, bestDist = known, d
+		}
+	}
+	if best != "" {
+		return fmt.Sprintf("unknown key (did you mean %s?)", best)
+	}
+	return "unknown key"
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+// joinKey appends a key to a dotted prefix.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
+
+// keySegment matches one segment of a dotted key with its list indexes, e.g. "steps[0]".
+var keySegment = regexp.MustCompile(`^([^\[]*)((?:\[\d+\])*)$`)
+
+// locate returns the position of the setting with the dotted key, or of its closest parent in the file.
+// It returns zeros when no part of the key is in the file.
+func locate(root *yaml.Node, key string) (line, column int) {
+	if root == nil || key == "" {
+		return 0, 0
+	}
+	n := root
+	for _, segment := range strings.Split(key, ".") {
+		m := keySegment.FindStringSubmatch(segment)
+		if m == nil {
+			return line, column
+		}
+		k, v := mappingEntry(n, m[1])
+		if v == nil {
+			return line, column
+		}
+		line, column = k.Line, k.Column
+		n = v
+		for _, index := range keyIndex.FindAllStringSubmatch(m[2], -1) {
+			i, _ := strconv.Atoi(index[1])
+			if n.Kind != yaml.SequenceNode || i >= len(n.Content) {
+				return line, column
+			}
+			n = n.Content[i]
+			line, column = n.Line, n.Column
+		}
+	}
+	return line, column
+}
+
+// mappingEntry returns the key and value nodes of an entry of a mapping node, matching the key case-insensitively like viper.
+func mappingEntry(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	i := entryIndex(n, key)
+	if i < 0 {
+		return nil, nil
+	}
+	return n.Content[i-1], n.Content[i]
+}
@@ -12,7 +12,10 @@ import (
 	"time"
 
 	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/history"
+	"github.com/tsuperis3112/pmdr/internal/hook"
 	"github.com/tsuperis3112/pmdr/internal/ipc"
+	"github.com/tsuperis3112/pmdr/internal/state"
 )
 
 // Run starts the pmdr daemon.
@@ -30,13 +33,47 @@ func Run() error {
 		}
 	}()
 
+	if err := config.ValidateUsed(); err != nil {
+		return err
+	}
 	cfg, err := config.Load()
 	if err != nil {
 		return err
 	}
 
 	timer := NewTimer(cfg)
-	service := NewPmdrService(timer)
+
+	// Restore the session that was running when the daemon last exited.
+	statePath, err := state.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("failed to get state path: %w", err)
+	}
+	store := state.NewStore(statePath)
+	snap, err := store.Load()
+	if err != nil {
+		slog.Warn("Discarding unreadable timer state", "error", err)
+	}
+	timer.SetStore(store)
+
+	historyPath, err := history.DefaultPath()
+	if err != nil {
+		return fmt.Errorf("failed to get history path: %w", err)
+	}
+	timer.SetJournal(history.NewJournal(historyPath))
+
+	hookLogPath, err := hook.DefaultLogPath()
+	if err != nil {
+		return fmt.Errorf("failed to get hook log path: %w", err)
+	}
+	hooks := hook.Runner{Timeout: cfg.HookTimeout, OutputLevel: cfg.HookOutputLevel, Log: hook.NewLog(hookLogPath)}
+	timer.SetHookLog(hooks.Log)
+
+	broadcaster := NewBroadcaster()
+	timer.SetEventHandler(broadcaster.Publish)
+	timer.Restore(snap)
+
+	reloader := NewReloader(timer, config.Reload)
+	service := NewPmdrService(timer, reloader)
 
 	if err := rpc.RegisterName(ipc.ServiceName, service); err != nil {
 		return err
@@ -58,6 +95,24 @@ func Run() error {
 		}
 	}()
 
+	// Stream timer events to watchers on a separate socket, since net/rpc cannot push.
+	watchSocketPath := ipc.GetWatchSocketPath()
+	if err := os.RemoveAll(watchSocketPath); err != nil {
+		return err
+	}
+	watchListener, err := net.Listen("unix", watchSocketPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := watchListener.Close(); err != nil {
+			slog.Error("Failed to close watch listener", "error", err)
+		}
+	}()
+	go broadcaster.Serve(watchListener, func() ipc.Event {
+		return ipc.Event{Type: ipc.EventStatus, Time: time.Now(), Status: timer.Status()}
+	})
+
 	// Create a ticker that will advance the timer state.
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
@@ -70,6 +125,25 @@ func Run() error {
 	}()
 
 	slog.Info("Daemon listening on", "socket", socketPath)
+	go hooks.Run(cfg.Hooks.Commands(config.EventDaemonStart, config.AnySession), hook.Event{Event: config.EventDaemonStart})
+
+	// Reload the configuration when the file changes.
+	if configPath, err := config.GetConfigFilePath(); err != nil {
+		slog.Warn("Not watching the config file", "error", err)
+	} else if stop, err := reloader.Watch(configPath); err != nil {
+		slog.Warn("Not watching the config file", "error", err)
+	} else {
+		defer func() { _ = stop() }()
+	}
+
+	// Reload the configuration on SIGHUP.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		for range hupCh {
+			reloader.reloadLogged()
+		}
+	}()
 
 	// Handle signals for graceful shutdown
 	sigCh := make(chan os.Signal, 1)
@@ -77,9 +151,17 @@ func Run() error {
 	go func() {
 		<-sigCh
 		slog.Info("Shutting down daemon")
+		// Wait for the shutdown hooks, which are bounded by their timeouts, before exiting.
+		// The runner is rebuilt since the configuration may have been reloaded.
+		cfg := timer.Config()
+		hooks := hook.Runner{Timeout: cfg.HookTimeout, OutputLevel: cfg.HookOutputLevel, Log: hooks.Log}
+		hooks.Run(cfg.Hooks.Commands(config.EventDaemonShutdown, config.AnySession), hook.Event{Event: config.EventDaemonShutdown})
 		if err := listener.Close(); err != nil {
 			slog.Error("Failed to close listener", "error", err)
 		}
+		if err := watchListener.Close(); err != nil {
+			slog.Error("Failed to close watch listener", "error", err)
+		}
 		os.Exit(0)
 	}()
 
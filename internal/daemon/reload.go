@@ -0,0 +1,98 @@
+package daemon
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// reloadDelay is how long the config file must stay unchanged before it is reloaded,
+// since editors often write a file in several steps.
+const reloadDelay = 200 * time.Millisecond
+
+// Reloader loads the configuration again and hands it to the timer.
+// Reloads from the file watcher, SIGHUP and the Reload RPC are serialized.
+type Reloader struct {
+	mu    sync.Mutex
+	timer *Timer
+	load  func() (*config.Config, error)
+}
+
+// NewReloader creates a Reloader that loads the configuration with load.
+func NewReloader(t *Timer, load func() (*config.Config, error)) *Reloader {
+	return &Reloader{timer: t, load: load}
+}
+
+// Reload loads and validates the configuration and replaces the one of the timer.
+// An invalid configuration is rejected and the current one is kept.
+func (r *Reloader) Reload(apply bool) (ipc.ReloadReply, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, err := r.load()
+	if err != nil {
+		return ipc.ReloadReply{}, fmt.Errorf("keeping the current configuration: %w", err)
+	}
+
+	changed := config.Diff(r.timer.Config(), cfg)
+	applied, err := r.timer.Reload(cfg, apply)
+	if err != nil {
+		return ipc.ReloadReply{}, fmt.Errorf("failed to apply the configuration to the running session: %w", err)
+	}
+	session := r.timer.Status().State != ipc.StateStopped
+	slog.Info("Reloaded configuration", "changed", changed, "applied", applied)
+	return ipc.ReloadReply{Changed: changed, Applied: applied, Session: session}, nil
+}
+
+// reloadLogged reloads the configuration for the watcher and SIGHUP, which have nobody to report errors to.
+func (r *Reloader) reloadLogged() {
+	if _, err := r.Reload(false); err != nil {
+		slog.Error("Failed to reload configuration", "error", err)
+	}
+}
+
+// Watch reloads the configuration whenever the file at path is written, created or replaced.
+// The directory is watched rather than the file so that files replaced by editors keep being watched.
+// The returned function stops watching.
+func (r *Reloader) Watch(path string) (func() error, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch config file: %w", err)
+	}
+
+	path = filepath.Clean(path)
+	go func() {
+		var timer *time.Timer
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) != path || !event.Has(fsnotify.Write|fsnotify.Create|fsnotify.Rename) {
+					continue
+				}
+				if timer != nil {
+					timer.Stop()
+				}
+				timer = time.AfterFunc(reloadDelay, r.reloadLogged)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				slog.Warn("Config file watcher failed", "error", err)
+			}
+		}
+	}()
+	return watcher.Close, nil
+}
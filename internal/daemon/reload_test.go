@@ -0,0 +1,68 @@
+package daemon
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+func TestReloader(t *testing.T) {
+	cfg := &config.Config{WorkDuration: 10 * time.Second, ShortBreakDuration: 5 * time.Second, LongBreakDuration: 8 * time.Second, PomoCycles: 2}
+	tm := newTestTimer(cfg)
+
+	next := *cfg
+	var loadErr error
+	var loads atomic.Int32
+	r := NewReloader(tm.Timer, func() (*config.Config, error) {
+		loads.Add(1)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return &next, nil
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		tm.Start(&ipc.StartArgs{})
+		reply, err := r.Reload(false)
+		require.NoError(t, err)
+		assert.True(t, reply.Session)
+		assert.False(t, reply.Applied)
+		assert.Same(t, &next, tm.Config())
+	})
+
+	t.Run("invalid config is rejected", func(t *testing.T) {
+		loadErr = errors.New("pomo_cycles must be positive")
+		defer func() { loadErr = nil }()
+
+		_, err := r.Reload(false)
+		assert.ErrorContains(t, err, "keeping the current configuration")
+		assert.Same(t, &next, tm.Config())
+	})
+
+	t.Run("file changes", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "config.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("pomo_cycles: 4\n"), 0644))
+		stop, err := r.Watch(path)
+		require.NoError(t, err)
+		defer func() { _ = stop() }()
+
+		before := loads.Load()
+		require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(path), "other.yaml"), nil, 0644))
+		require.NoError(t, os.WriteFile(path, []byte("pomo_cycles: 3\n"), 0644))
+		require.Eventually(t, func() bool { return loads.Load() == before+1 }, 2*time.Second, 10*time.Millisecond)
+
+		// Editors that replace the file are followed too.
+		tmp := path + ".tmp"
+		require.NoError(t, os.WriteFile(tmp, []byte("pomo_cycles: 2\n"), 0644))
+		require.NoError(t, os.Rename(tmp, path))
+		require.Eventually(t, func() bool { return loads.Load() == before+2 }, 2*time.Second, 10*time.Millisecond)
+	})
+}
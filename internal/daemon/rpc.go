@@ -6,18 +6,18 @@ import (
 
 // PmdrService is the RPC service for pmdr.
 type PmdrService struct {
-	timer *Timer
+	timer    *Timer
+	reloader *Reloader
 }
 
 // NewPmdrService creates a new PmdrService.
-func NewPmdrService(t *Timer) *PmdrService {
-	return &PmdrService{timer: t}
+func NewPmdrService(t *Timer, r *Reloader) *PmdrService {
+	return &PmdrService{timer: t, reloader: r}
 }
 
 // Start starts the timer.
 func (s *PmdrService) Start(args *ipc.StartArgs, reply *struct{}) error {
-	s.timer.Start(args)
-	return nil
+	return s.timer.Start(args)
 }
 
 // Pause pauses the timer.
@@ -32,6 +32,52 @@ func (s *PmdrService) Resume(args *ipc.Args, reply *struct{}) error {
 	return nil
 }
 
+// Skip ends the current session and starts the next one.
+func (s *PmdrService) Skip(args *ipc.SkipArgs, reply *struct{}) error {
+	return s.timer.Skip(args.RunHooks)
+}
+
+// SetTask changes the task labels of the current session.
+func (s *PmdrService) SetTask(args *ipc.TaskArgs, reply *struct{}) error {
+	return s.timer.SetTask(args.Task, args.Tags)
+}
+
+// Next starts the session following a finished session.
+func (s *PmdrService) Next(args *ipc.Args, reply *struct{}) error {
+	return s.timer.Next()
+}
+
+// Extend moves the end of the current session.
+func (s *PmdrService) Extend(args *ipc.ExtendArgs, reply *ipc.ExtendReply) error {
+	r, err := s.timer.Extend(args.Duration)
+	if err != nil {
+		return err
+	}
+	*reply = r
+	return nil
+}
+
+// Mute silences sounds and speech.
+func (s *PmdrService) Mute(args *ipc.MuteArgs, reply *struct{}) error {
+	return s.timer.Mute(args.Duration)
+}
+
+// Unmute ends a mute.
+func (s *PmdrService) Unmute(args *ipc.Args, reply *struct{}) error {
+	s.timer.Unmute()
+	return nil
+}
+
+// Reload loads the configuration again.
+func (s *PmdrService) Reload(args *ipc.ReloadArgs, reply *ipc.ReloadReply) error {
+	r, err := s.reloader.Reload(args.Apply)
+	if err != nil {
+		return err
+	}
+	*reply = r
+	return nil
+}
+
 // Stop stops the timer.
 func (s *PmdrService) Stop(args *ipc.Args, reply *struct{}) error {
 	s.timer.Stop()
@@ -1,15 +1,29 @@
 package daemon
 
 import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/history"
 	"github.com/tsuperis3112/pmdr/internal/hook"
 	"github.com/tsuperis3112/pmdr/internal/ipc"
-	"github.com/tsuperis3112/pmdr/internal/sound"
+	"github.com/tsuperis3112/pmdr/internal/notify"
+	"github.com/tsuperis3112/pmdr/internal/state"
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
 )
 
+// ErrNoSession is returned when an operation needs a session but the timer is stopped.
+var ErrNoSession = errors.New("no session is in progress")
+
+// ErrNotDone is returned by Next when no finished session is waiting for a manual transition.
+var ErrNotDone = errors.New("no finished session is waiting; use skip to end the current one")
+
 // Timer is a state machine for the pomodoro timer.
 // It is designed to be thread-safe and does not manage its own ticker.
 type Timer struct {
@@ -24,6 +38,25 @@ type Timer struct {
 	nextSessionTime  time.Time
 	pauseTime        time.Time // Time when the timer was paused
 	pomoCycle        int
+	plannedDuration  time.Duration // Length of the current session, including extensions
+	pausedDuration   time.Duration // Time spent paused in the current session
+	profile          string        // Profile the session config was built from
+	sequence         string        // Sequence being run; empty for the pomodoro cycle
+	step             int           // Index of the current step in the sequence
+	task             string        // Work item the sessions are attributed to
+	tags             []string
+	tally            state.Tally     // Work done today, kept across sessions and restarts
+	remainingFired   bool            // Whether the remaining hooks already ran for the current session
+	warned           []time.Duration // Warning thresholds already reached in the current session
+	muted            bool            // Set by Mute; kept across sessions and restarts
+	mutedUntil       time.Time       // End of the mute; zero mutes until Unmute
+
+	store   *state.Store     // Persists every state change; nil disables persistence
+	journal *history.Journal // Records every finished session; nil disables the history
+	onEvent func(ipc.Event)  // Receives every event; must not block
+	hookLog *hook.Log        // Records every hook execution; nil disables the log
+
+	notifier notify.Notifier // Overrides the notifiers of the config when set
 
 	nowFunc func() time.Time
 }
@@ -49,7 +82,127 @@ func (t *Timer) Tick() {
 	now := t.nowFunc()
 	if !t.nextSessionTime.After(now) {
 		t.handleSessionCompletion()
+		t.persist()
+		return
+	}
+
+	before := t.sessionConfig.RemainingBefore
+	if before > 0 && !t.remainingFired && t.reached(before) {
+		t.remainingFired = true
+		t.trigger(config.EventRemaining)
+		t.persist()
 	}
+
+	if t.checkWarnings() {
+		t.trigger(config.EventWarning)
+		t.emit(ipc.EventWarning)
+		t.persist()
+	}
+}
+
+// reached reports whether no more than the threshold is left in the current session without locking.
+// Thresholds that are not shorter than the session are never reached, so they do not fire right at the start.
+func (t *Timer) reached(threshold time.Duration) bool {
+	return t.plannedDuration > threshold && t.remaining() <= threshold
+}
+
+// checkWarnings marks the warning thresholds reached since the last check without locking.
+// It reports whether a warning is due; thresholds reached together, e.g. after the daemon was down,
+// produce a single warning.
+func (t *Timer) checkWarnings() bool {
+	due := false
+	for _, w := range t.sessionConfig.Warnings.For(t.sessionType.String()) {
+		if !slices.Contains(t.warned, w) && t.reached(w) {
+			t.warned = append(t.warned, w)
+			due = true
+		}
+	}
+	return due
+}
+
+// SetStore sets the store used to persist the timer state.
+func (t *Timer) SetStore(store *state.Store) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.store = store
+}
+
+// SetEventHandler sets the function that receives every timer event.
+// It is called with the timer locked, so it must not block or call back into the timer.
+func (t *Timer) SetEventHandler(fn func(ipc.Event)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.onEvent = fn
+}
+
+// SetNotifier replaces the notifiers of the configuration with n.
+func (t *Timer) SetNotifier(n notify.Notifier) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.notifier = n
+}
+
+// SetHookLog sets the log used to record hook executions.
+func (t *Timer) SetHookLog(log *hook.Log) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.hookLog = log
+}
+
+// SetJournal sets the journal used to record finished sessions.
+func (t *Timer) SetJournal(journal *history.Journal) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.journal = journal
+}
+
+// Restore resumes the session described by a snapshot.
+// A running session whose end time passed while the daemon was down is completed,
+// so its hooks fire and the next session starts from now.
+func (t *Timer) Restore(snap *state.Snapshot) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if snap == nil {
+		return
+	}
+	t.tally = snap.Tally
+	t.muted = snap.Muted
+	t.mutedUntil = snap.MutedUntil
+
+	if snap.State == ipc.StateStopped || snap.SessionConfig == nil {
+		return
+	}
+
+	t.state = snap.State
+	t.sessionType = snap.SessionType
+	t.startSessionTime = snap.StartSessionTime
+	t.nextSessionTime = snap.NextSessionTime
+	t.pauseTime = snap.PauseTime
+	t.pomoCycle = snap.PomoCycle
+	t.plannedDuration = snap.PlannedDuration
+	t.pausedDuration = snap.PausedDuration
+	t.profile = snap.Profile
+	t.sequence = snap.Sequence
+	t.step = snap.Step
+	t.task = snap.Task
+	t.tags = snap.Tags
+	t.remainingFired = snap.RemainingFired
+	t.warned = snap.Warned
+	t.sessionConfig = snap.SessionConfig
+
+	slog.Info("Restored timer state", "state", t.state, "session_type", t.sessionType, "saved_at", snap.SavedAt)
+
+	if t.state == ipc.StateRunning && !t.nextSessionTime.After(t.nowFunc()) {
+		slog.Info("Session ended while the daemon was down, completing it", "ended_at", t.nextSessionTime)
+		t.handleSessionCompletion()
+	}
+	t.persist()
 }
 
 // Status returns the current status of the timer.
@@ -57,38 +210,104 @@ func (t *Timer) Status() ipc.StatusReply {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
-	var remainingTime time.Duration
-	if t.state == ipc.StatePaused {
-		remainingTime = t.nextSessionTime.Sub(t.pauseTime)
-	} else {
-		remainingTime = t.nextSessionTime.Sub(t.nowFunc())
-	}
+	return t.status()
+}
 
+// status returns the current status of the timer without locking.
+func (t *Timer) status() ipc.StatusReply {
+	remainingTime := t.remaining()
 	if remainingTime < 0 {
 		remainingTime = 0
 	}
 
-	return ipc.StatusReply{
+	var overtime time.Duration
+	if t.state == ipc.StateDone {
+		overtime = t.nowFunc().Sub(t.nextSessionTime)
+	}
+
+	reply := ipc.StatusReply{
 		State:         t.state,
 		SessionType:   t.sessionType,
 		RemainingTime: remainingTime,
 		EndTime:       t.nextSessionTime,
 		PomoCycle:     t.pomoCycle,
+		Overtime:      overtime,
+		Profile:       t.profile,
+		Sequence:      t.sequence,
+		Task:          t.task,
+		Tags:          slices.Clone(t.tags),
+	}
+	if step, ok := t.currentStep(); ok {
+		reply.Step = step.Label()
+		reply.StepIndex = t.step + 1
+		reply.StepCount = len(t.sessionConfig.Sequences[t.sequence].Steps)
+	}
+
+	if t.isMuted() {
+		reply.Muted = true
+		reply.MutedUntil = t.mutedUntil
 	}
+	reply.QuietHours = t.config().QuietHours.Contains(t.nowFunc())
+
+	tally := t.today()
+	goal := t.config().DailyGoal
+	reply.TodayPomodoros = tally.Pomodoros
+	reply.TodayFocus = tally.Focus
+	reply.GoalPomodoros = goal.Pomodoros
+	reply.GoalFocus = goal.Focus
+	return reply
 }
 
 // Start begins a new session.
-func (t *Timer) Start(args *ipc.StartArgs) {
+// The named profile, or the default profile if none is given, is applied before the overrides in args.
+func (t *Timer) Start(args *ipc.StartArgs) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
 	if t.state == ipc.StateRunning {
-		return
+		return nil
+	}
+
+	profile := args.Profile
+	if profile == "" {
+		profile = t.globalConfig.DefaultProfile
+	}
+	cfg, err := sessionConfig(t.globalConfig, profile, args)
+	if err != nil {
+		return err
 	}
 
 	t.stopInternal()
 
-	cfg := *t.globalConfig
+	t.sessionConfig = cfg
+	t.profile = profile
+	t.sequence = cfg.Sequence
+	t.step = 0
+	t.task = args.Task
+	t.tags = slices.Clone(args.Tags)
+
+	t.pomoCycle = 1
+	if t.sequence != "" {
+		t.startStep()
+	} else {
+		t.startSession(ipc.TypeWork)
+	}
+	t.persist()
+	return nil
+}
+
+// sessionConfig builds the configuration of a session from the global configuration,
+// the profile and the overrides given when the session started.
+func sessionConfig(global *config.Config, profile string, args *ipc.StartArgs) (*config.Config, error) {
+	base := global
+	if profile != "" {
+		var err error
+		if base, err = base.WithProfile(profile); err != nil {
+			return nil, err
+		}
+	}
+
+	cfg := *base
 	if args.WorkDuration != nil {
 		cfg.WorkDuration = *args.WorkDuration
 	}
@@ -101,10 +320,69 @@ func (t *Timer) Start(args *ipc.StartArgs) {
 	if args.PomoCycles != nil {
 		cfg.PomoCycles = *args.PomoCycles
 	}
-	t.sessionConfig = &cfg
+	if args.Sequence != "" {
+		cfg.Sequence = args.Sequence
+	}
+	if _, ok := cfg.Sequences[cfg.Sequence]; cfg.Sequence != "" && !ok {
+		return nil, fmt.Errorf("unknown sequence %q", cfg.Sequence)
+	}
+	return &cfg, nil
+}
 
-	t.pomoCycle = 1
-	t.startSession(ipc.TypeWork)
+// Config returns the global configuration.
+func (t *Timer) Config() *config.Config {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.globalConfig
+}
+
+// Reload replaces the global configuration, which is used from the next "pmdr start" on.
+// The running session keeps its configuration unless apply is set. Applied changes take effect
+// from the next session on, except for thresholds, hooks and notifications, which apply right away;
+// durations, cycles and the sequence given to "pmdr start" are kept.
+// It reports whether the changes were applied to a session.
+func (t *Timer) Reload(cfg *config.Config, apply bool) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	applied := false
+	if apply && t.sessionConfig != nil {
+		next, err := t.reloadSession(cfg)
+		if err != nil {
+			return false, err
+		}
+		t.sessionConfig = next
+		applied = true
+	}
+	t.globalConfig = cfg
+	t.emit(ipc.EventConfigReloaded)
+	t.persist()
+	return applied, nil
+}
+
+// reloadSession rebuilds the configuration of the current session from cfg without locking.
+// Settings that were overridden when the session started keep their values.
+func (t *Timer) reloadSession(cfg *config.Config) (*config.Config, error) {
+	old, err := sessionConfig(t.globalConfig, t.profile, &ipc.StartArgs{})
+	if err != nil {
+		return nil, err
+	}
+	cur := t.sessionConfig
+	args := &ipc.StartArgs{Sequence: t.sequence}
+	if cur.WorkDuration != old.WorkDuration {
+		args.WorkDuration = &cur.WorkDuration
+	}
+	if cur.ShortBreakDuration != old.ShortBreakDuration {
+		args.ShortBreakDuration = &cur.ShortBreakDuration
+	}
+	if cur.LongBreakDuration != old.LongBreakDuration {
+		args.LongBreakDuration = &cur.LongBreakDuration
+	}
+	if cur.PomoCycles != old.PomoCycles {
+		args.PomoCycles = &cur.PomoCycles
+	}
+	return sessionConfig(cfg, t.profile, args)
 }
 
 // Pause pauses the timer.
@@ -117,6 +395,9 @@ func (t *Timer) Pause() {
 	}
 	t.state = ipc.StatePaused
 	t.pauseTime = t.nowFunc()
+	t.trigger(config.EventPause)
+	t.emit(ipc.EventPaused)
+	t.persist()
 }
 
 // Resume resumes the timer.
@@ -129,7 +410,143 @@ func (t *Timer) Resume() {
 	}
 	durationPaused := t.nowFunc().Sub(t.pauseTime)
 	t.nextSessionTime = t.nextSessionTime.Add(durationPaused)
+	t.pausedDuration += durationPaused
 	t.state = ipc.StateRunning
+	t.trigger(config.EventResume)
+	t.emit(ipc.EventResumed)
+	t.persist()
+}
+
+// Skip ends the current session immediately and moves on to the next one,
+// exactly as if the session had run to completion.
+// A finished session waiting in StateDone is continued as with Next.
+func (t *Timer) Skip(runHooks bool) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state == ipc.StateDone {
+		t.next()
+		t.persist()
+		return nil
+	}
+	if t.state != ipc.StateRunning && t.state != ipc.StatePaused {
+		return ErrNoSession
+	}
+	t.endSession(history.OutcomeSkipped, t.nowFunc(), runHooks)
+	t.persist()
+	return nil
+}
+
+// SetTask changes the task and tags of the current session.
+// They also apply to the following sessions until changed again.
+func (t *Timer) SetTask(task string, tags []string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state == ipc.StateStopped {
+		return ErrNoSession
+	}
+	t.task = task
+	t.tags = slices.Clone(tags)
+	t.emit(ipc.EventTaskChanged)
+	t.persist()
+	return nil
+}
+
+// Next starts the session that follows a finished session waiting in StateDone.
+func (t *Timer) Next() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state != ipc.StateDone {
+		return ErrNotDone
+	}
+	t.next()
+	t.persist()
+	return nil
+}
+
+// Extend moves the end of the current session by d, which may be negative.
+// The change is limited so that the session stays within the configured bounds.
+func (t *Timer) Extend(d time.Duration) (ipc.ExtendReply, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state != ipc.StateRunning && t.state != ipc.StatePaused {
+		return ipc.ExtendReply{}, ErrNoSession
+	}
+
+	bounds := t.sessionConfig.Extend
+	applied := d
+	if d > 0 && bounds.MaxDuration > 0 {
+		length := t.nextSessionTime.Sub(t.startSessionTime) - t.pausedDuration
+		applied = min(applied, max(bounds.MaxDuration-length, 0))
+	}
+	if d < 0 {
+		applied = max(applied, min(bounds.MinRemaining-t.remaining(), 0))
+	}
+
+	t.nextSessionTime = t.nextSessionTime.Add(applied)
+	t.plannedDuration += applied
+	if t.remaining() > t.sessionConfig.RemainingBefore {
+		t.remainingFired = false
+	}
+	// Warnings that are ahead again after extending the session are given again.
+	t.warned = slices.DeleteFunc(t.warned, func(w time.Duration) bool { return t.remaining() > w })
+	t.emit(ipc.EventExtended)
+	t.persist()
+
+	return ipc.ExtendReply{
+		Applied:       applied,
+		RemainingTime: max(t.remaining(), 0),
+		EndTime:       t.nextSessionTime,
+	}, nil
+}
+
+// Mute silences sounds and speech for d, or until Unmute when d is zero.
+// Hooks still run and silent notifications are still delivered.
+func (t *Timer) Mute(d time.Duration) error {
+	if d < 0 {
+		return fmt.Errorf("invalid mute duration %s", d)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.muted = true
+	t.mutedUntil = time.Time{}
+	if d > 0 {
+		t.mutedUntil = t.nowFunc().Add(d)
+		slog.Info("Muted notifications", "until", t.mutedUntil)
+	} else {
+		slog.Info("Muted notifications until unmuted")
+	}
+	t.emit(ipc.EventMuted)
+	t.persist()
+	return nil
+}
+
+// Unmute ends a mute started with Mute.
+func (t *Timer) Unmute() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.muted = false
+	t.mutedUntil = time.Time{}
+	slog.Info("Unmuted notifications")
+	t.emit(ipc.EventUnmuted)
+	t.persist()
+}
+
+// isMuted reports whether a mute started with Mute is in effect without locking.
+func (t *Timer) isMuted() bool {
+	return t.muted && (t.mutedUntil.IsZero() || t.mutedUntil.After(t.nowFunc()))
+}
+
+// silenced reports whether notifications should make no sound, because of a mute
+// or the quiet hours, without locking.
+func (t *Timer) silenced() bool {
+	return t.isMuted() || t.config().QuietHours.Contains(t.nowFunc())
 }
 
 // Stop stops the timer completely.
@@ -138,25 +555,292 @@ func (t *Timer) Stop() {
 	defer t.mu.Unlock()
 
 	t.stopInternal()
+	t.persist()
 }
 
 // stopInternal stops the timer without locking.
 func (t *Timer) stopInternal() {
+	if t.state == ipc.StateRunning || t.state == ipc.StatePaused {
+		t.record(history.OutcomeStopped, t.nowFunc())
+	}
+	if t.state == ipc.StateDone {
+		t.record(history.OutcomeCompleted, t.nextSessionTime)
+	}
+	wasActive := t.state != ipc.StateStopped
+	if wasActive {
+		t.trigger(config.EventStop)
+	}
 	t.state = ipc.StateStopped
+	t.clearSession()
+	if wasActive {
+		t.emit(ipc.EventStopped)
+	}
+}
+
+// clearSession forgets the config, profile and sequence of the stopped timer without locking.
+func (t *Timer) clearSession() {
 	t.sessionConfig = nil
+	t.profile = ""
+	t.sequence = ""
+	t.step = 0
 }
 
-// startSession starts a new session of the given type.
-func (t *Timer) startSession(st ipc.SessionType) {
-	switch st {
-	case ipc.TypeWork:
-		sound.Notify(sound.Work)
-	case ipc.TypeShortBreak:
-		sound.Notify(sound.ShortBreak)
-	case ipc.TypeLongBreak:
-		sound.Notify(sound.LongBreak)
+// emit sends an event with the current status to the event handler without locking.
+func (t *Timer) emit(eventType ipc.EventType) {
+	if t.onEvent == nil {
+		return
+	}
+	t.onEvent(ipc.Event{
+		Type:   eventType,
+		Time:   t.nowFunc(),
+		Status: t.status(),
+	})
+}
+
+// remaining returns the time left in the current session without locking.
+// While paused, the clock is frozen at the time the timer was paused.
+func (t *Timer) remaining() time.Duration {
+	if t.state == ipc.StatePaused {
+		return t.nextSessionTime.Sub(t.pauseTime)
+	}
+	return t.nextSessionTime.Sub(t.nowFunc())
+}
+
+// snapshot returns a serializable copy of the timer state without locking.
+func (t *Timer) snapshot() *state.Snapshot {
+	return &state.Snapshot{
+		State:            t.state,
+		SessionType:      t.sessionType,
+		StartSessionTime: t.startSessionTime,
+		NextSessionTime:  t.nextSessionTime,
+		PauseTime:        t.pauseTime,
+		PomoCycle:        t.pomoCycle,
+		PlannedDuration:  t.plannedDuration,
+		PausedDuration:   t.pausedDuration,
+		Profile:          t.profile,
+		Sequence:         t.sequence,
+		Step:             t.step,
+		Task:             t.task,
+		Tags:             slices.Clone(t.tags),
+		SessionConfig:    t.sessionConfig,
+		Tally:            t.tally,
+		RemainingFired:   t.remainingFired,
+		Warned:           slices.Clone(t.warned),
+		Muted:            t.muted,
+		MutedUntil:       t.mutedUntil,
+		SavedAt:          t.nowFunc(),
+	}
+}
+
+// persist saves the current state to the store without locking.
+func (t *Timer) persist() {
+	if t.store == nil {
+		return
+	}
+	if err := t.store.Save(t.snapshot()); err != nil {
+		slog.Error("Failed to save timer state", "error", err, "path", t.store.Path())
+	}
+}
+
+// sessionEntry describes the current session as a history entry ending at the given time.
+func (t *Timer) sessionEntry(outcome history.Outcome, end time.Time) history.Entry {
+	paused := t.pausedDuration
+	if t.state == ipc.StatePaused {
+		paused += end.Sub(t.pauseTime)
+	}
+	e := history.Entry{
+		Type:    t.sessionType,
+		Start:   t.startSessionTime,
+		End:     end,
+		Planned: t.plannedDuration,
+		Actual:  end.Sub(t.startSessionTime) - paused,
+		Paused:  paused,
+		Cycle:   t.pomoCycle,
+		Outcome: outcome,
+		Task:    t.task,
+		Tags:    slices.Clone(t.tags),
+	}
+	if step, ok := t.currentStep(); ok {
+		e.Step = step.Label()
+	}
+	if t.state == ipc.StateDone {
+		e.Overtime = t.nowFunc().Sub(t.nextSessionTime)
+	}
+	return e
+}
+
+// record appends the current session to the journal and counts it toward the daily goal without locking.
+func (t *Timer) record(outcome history.Outcome, end time.Time) {
+	entry := t.sessionEntry(outcome, end)
+	t.countTowardGoal(entry)
+
+	if t.journal == nil {
+		return
+	}
+	if err := t.journal.Append(entry); err != nil {
+		slog.Error("Failed to record session history", "error", err, "path", t.journal.Path())
+	}
+}
+
+// config returns the configuration in effect without locking.
+func (t *Timer) config() *config.Config {
+	if t.sessionConfig != nil {
+		return t.sessionConfig
+	}
+	return t.globalConfig
+}
+
+// today returns the tally for the current day without locking.
+func (t *Timer) today() state.Tally {
+	date := t.nowFunc().Format(time.DateOnly)
+	if t.tally.Date != date {
+		return state.Tally{Date: date}
+	}
+	return t.tally
+}
+
+// countTowardGoal adds a finished work session to today's tally
+// and announces the daily goal the first time it is reached.
+func (t *Timer) countTowardGoal(e history.Entry) {
+	if e.Type != ipc.TypeWork {
+		return
+	}
+
+	t.tally = t.today()
+	t.tally.Focus += e.Actual
+	if e.Outcome == history.OutcomeCompleted {
+		t.tally.Pomodoros++
+	}
+
+	goal := t.config().DailyGoal
+	if t.tally.GoalReached || !goal.Reached(t.tally.Pomodoros, t.tally.Focus) {
+		return
+	}
+	t.tally.GoalReached = true
+	slog.Info("Daily goal reached", "pomodoros", t.tally.Pomodoros, "focus", t.tally.Focus)
+	t.emit(ipc.EventGoalReached)
+	t.trigger(config.EventGoalReached)
+}
+
+// trigger runs the hooks for the event and the current session type
+// and sends its notification in the background without locking.
+func (t *Timer) trigger(event string) {
+	t.triggerAt(event, t.nowFunc())
+}
+
+// triggerAt is trigger for an event that happened at a given time, such as the end of a session.
+func (t *Timer) triggerAt(event string, at time.Time) {
+	e := t.hookEvent(event, at)
+	go t.hookRunner().Run(t.config().Hooks.Commands(event, t.sessionType.String()), e)
+
+	n := t.currentNotifier()
+	if n == nil {
+		return
+	}
+	m := t.message(e)
+	m.Muted = t.silenced()
+	go func() {
+		if err := n.Notify(m); err != nil {
+			slog.Warn("Failed to deliver notification", "error", err, "event", event)
+		}
+	}()
+}
+
+// currentNotifier returns the notifier set with SetNotifier,
+// or one built from the configuration of the current session, without locking.
+func (t *Timer) currentNotifier() notify.Notifier {
+	if t.notifier != nil {
+		return t.notifier
+	}
+	n, err := notify.New(t.config())
+	if err != nil {
+		slog.Error("Invalid notifier configuration", "error", err)
+		return nil
+	}
+	return n
+}
+
+// message builds the notification for the event without locking.
+func (t *Timer) message(e hook.Event) notify.Message {
+	m := notify.Message{Event: e.Event, Text: t.spokenText(e), Context: e}
+
+	n, ok := t.config().Desktop.For(e.Event)
+	if !ok {
+		return m
+	}
+	var err error
+	if m.Title, err = tmpl.Render(n.Title, e); err != nil {
+		slog.Error("Failed to render desktop notification title", "error", err, "event", e.Event)
+	}
+	if m.Body, err = tmpl.Render(n.Body, e); err != nil {
+		slog.Error("Failed to render desktop notification body", "error", err, "event", e.Event)
+	}
+	m.Icon = n.Icon
+	m.Urgency = n.Urgency
+	return m
+}
+
+// spokenText renders the spoken message of the event without locking.
+// Events that are not announced return an empty string.
+func (t *Timer) spokenText(e hook.Event) string {
+	switch e.Event {
+	case config.EventComplete:
+		// Otherwise the start of the next session is announced right after it.
+		if t.state != ipc.StateDone {
+			return ""
+		}
+	case config.EventStop:
+		// Stopping by hand is not announced, only the end of a sequence.
+		if t.sequence == "" || t.sessionConfig == nil || t.step < len(t.sessionConfig.Sequences[t.sequence].Steps) {
+			return ""
+		}
 	}
 
+	text, err := tmpl.Render(t.config().TTS.Message(e.Event), e)
+	if err != nil {
+		slog.Error("Failed to render spoken message", "error", err, "event", e.Event)
+		return ""
+	}
+	return strings.TrimSpace(text)
+}
+
+// hookRunner returns a runner configured for the current session without locking.
+func (t *Timer) hookRunner() hook.Runner {
+	cfg := t.config()
+	return hook.Runner{Timeout: cfg.HookTimeout, OutputLevel: cfg.HookOutputLevel, Log: t.hookLog}
+}
+
+// hookEvent describes the current session at the time of the event to hooks without locking.
+// For the complete, skip and stop events, that is the time the session ended.
+func (t *Timer) hookEvent(event string, at time.Time) hook.Event {
+	e := hook.Event{
+		Event:       event,
+		SessionType: t.sessionType.String(),
+		Cycle:       t.pomoCycle,
+		StartedAt:   t.startSessionTime,
+		Duration:    t.plannedDuration,
+		Task:        t.task,
+		Tags:        slices.Clone(t.tags),
+	}
+	switch event {
+	case config.EventComplete, config.EventSkip, config.EventStop:
+		e.EndedAt = at
+	default:
+		e.Remaining = max(t.remaining(), 0)
+	}
+	paused := t.pausedDuration
+	if t.state == ipc.StatePaused {
+		paused += at.Sub(t.pauseTime)
+	}
+	e.Elapsed = max(at.Sub(t.startSessionTime)-paused, 0)
+	if step, ok := t.currentStep(); ok {
+		e.Step = step.Label()
+	}
+	return e
+}
+
+// startSession starts a new session of the given type.
+func (t *Timer) startSession(st ipc.SessionType) {
 	t.sessionType = st
 	t.state = ipc.StateRunning
 	now := t.nowFunc()
@@ -170,26 +854,98 @@ func (t *Timer) startSession(st ipc.SessionType) {
 	case ipc.TypeLongBreak:
 		t.nextSessionTime = now.Add(t.sessionConfig.LongBreakDuration)
 	}
+	if step, ok := t.currentStep(); ok {
+		t.nextSessionTime = now.Add(step.Duration)
+	}
+	t.plannedDuration = t.nextSessionTime.Sub(now)
+	t.pausedDuration = 0
+	t.remainingFired = false
+	t.warned = nil
+	t.trigger(config.EventStart)
+	t.emit(ipc.EventStarted)
+}
+
+// currentStep returns the current step when a sequence is being run.
+func (t *Timer) currentStep() (config.Step, bool) {
+	if t.sequence == "" || t.sessionConfig == nil {
+		return config.Step{}, false
+	}
+	steps := t.sessionConfig.Sequences[t.sequence].Steps
+	if t.step < 0 || t.step >= len(steps) {
+		return config.Step{}, false
+	}
+	return steps[t.step], true
+}
+
+// startStep starts a session for the current step of the sequence.
+func (t *Timer) startStep() {
+	step, _ := t.currentStep()
+	st, err := ipc.ParseSessionType(step.Type)
+	if err != nil {
+		slog.Error("Invalid sequence step, treating it as work", "sequence", t.sequence, "step", step.Label(), "error", err)
+		st = ipc.TypeWork
+	}
+	t.startSession(st)
 }
 
 // handleSessionCompletion decides what to do after a session ends.
 func (t *Timer) handleSessionCompletion() {
+	t.endSession(history.OutcomeCompleted, t.nextSessionTime, true)
+}
+
+// endSession records the current session as ending at the given time,
+// optionally runs its completion hooks, and starts the next session.
+// The skip hooks of a skipped session always run.
+// A completed session that is configured for manual transitions waits in StateDone instead.
+func (t *Timer) endSession(outcome history.Outcome, end time.Time, runHooks bool) {
 	if t.state == ipc.StateStopped {
 		return
 	}
 
-	completedSession := t.sessionType
+	waits := outcome == history.OutcomeCompleted && t.sessionConfig.ManualTransition.For(t.sessionType.String())
+	if waits {
+		// Set before the hooks run so the completion is announced as a wait.
+		t.state = ipc.StateDone
+	}
 
-	switch completedSession {
-	case ipc.TypeWork:
-		go hook.Run(t.sessionConfig.Hooks.Work)
-	case ipc.TypeShortBreak:
-		go hook.Run(t.sessionConfig.Hooks.ShortBreak)
-	case ipc.TypeLongBreak:
-		go hook.Run(t.sessionConfig.Hooks.LongBreak)
+	if outcome == history.OutcomeSkipped {
+		t.triggerAt(config.EventSkip, end)
+	}
+	if runHooks {
+		t.triggerAt(config.EventComplete, end)
+		if step, ok := t.currentStep(); ok {
+			go t.hookRunner().Run(step.Hooks, t.hookEvent(config.EventComplete, end))
+		}
 	}
 
-	if completedSession == ipc.TypeWork {
+	if waits {
+		t.emit(ipc.EventCompleted)
+		return
+	}
+
+	if outcome == history.OutcomeSkipped {
+		t.emit(ipc.EventSkipped)
+	} else {
+		t.emit(ipc.EventCompleted)
+	}
+	t.record(outcome, end)
+	t.advance()
+}
+
+// next records a session waiting in StateDone, including its overtime, and starts the next session.
+func (t *Timer) next() {
+	t.record(history.OutcomeCompleted, t.nextSessionTime)
+	t.advance()
+}
+
+// advance starts the session that follows the current one.
+func (t *Timer) advance() {
+	if t.sequence != "" {
+		t.advanceSequence()
+		return
+	}
+
+	if t.sessionType == ipc.TypeWork {
 		if t.pomoCycle >= t.sessionConfig.PomoCycles {
 			t.pomoCycle = 0
 			t.startSession(ipc.TypeLongBreak)
@@ -201,3 +957,22 @@ func (t *Timer) handleSessionCompletion() {
 		t.startSession(ipc.TypeWork)
 	}
 }
+
+// advanceSequence starts the next step of the sequence.
+// After the last step the sequence starts over with the next cycle, or the timer stops if it does not loop.
+func (t *Timer) advanceSequence() {
+	t.step++
+	if t.step >= len(t.sessionConfig.Sequences[t.sequence].Steps) {
+		if !t.sessionConfig.Sequences[t.sequence].Loop {
+			slog.Info("Sequence finished", "sequence", t.sequence)
+			t.trigger(config.EventStop)
+			t.state = ipc.StateStopped
+			t.clearSession()
+			t.emit(ipc.EventStopped)
+			return
+		}
+		t.step = 0
+		t.pomoCycle++
+	}
+	t.startStep()
+}
@@ -1,12 +1,18 @@
 package daemon
 
 import (
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/history"
 	"github.com/tsuperis3112/pmdr/internal/ipc"
+	"github.com/tsuperis3112/pmdr/internal/notify"
+	"github.com/tsuperis3112/pmdr/internal/state"
 )
 
 // testTimer is a wrapper around the Timer that allows for time manipulation.
@@ -120,6 +126,48 @@ func TestTimerStateTransitions(t *testing.T) {
 		assert.Equal(t, remainingBeforePause-time.Second, tm.Status().RemainingTime)
 	})
 
+	t.Run("skip work session", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(3 * time.Second)
+
+		assert.NoError(t, tm.Skip(false))
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeShortBreak, status.SessionType)
+		assert.Equal(t, 1, status.PomoCycle)
+		assert.Equal(t, 5*time.Second, status.RemainingTime)
+	})
+
+	t.Run("skip paused break", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(10 * time.Second) // work
+		tm.Pause()
+
+		assert.NoError(t, tm.Skip(false))
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, 2, status.PomoCycle)
+	})
+
+	t.Run("skip without a session", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		assert.ErrorIs(t, tm.Skip(false), ErrNoSession)
+	})
+
+	t.Run("unknown profile keeps the current session", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		tm.Start(&ipc.StartArgs{})
+		tm.Pause()
+
+		assert.ErrorContains(t, tm.Start(&ipc.StartArgs{Profile: "missing"}), "unknown profile")
+		assert.Equal(t, ipc.StatePaused, tm.Status().State)
+	})
+
 	t.Run("stop timer", func(t *testing.T) {
 		tm := newTestTimer(baseConfig)
 		tm.Start(&ipc.StartArgs{})
@@ -128,3 +176,760 @@ func TestTimerStateTransitions(t *testing.T) {
 		assert.Equal(t, ipc.StateStopped, tm.Status().State)
 	})
 }
+
+func TestTimerRestore(t *testing.T) {
+	baseConfig := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+	}
+
+	// snapshotOf starts a work session, lets d elapse and captures the resulting state.
+	snapshotOf := func(d time.Duration, pause bool) *state.Snapshot {
+		tm := newTestTimer(baseConfig)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(d)
+		if pause {
+			tm.Pause()
+		}
+		return tm.snapshot()
+	}
+
+	t.Run("running session continues after restart", func(t *testing.T) {
+		snap := snapshotOf(3*time.Second, false)
+
+		tm := newTestTimer(baseConfig)
+		tm.currentTime = tm.currentTime.Add(4 * time.Second)
+		tm.Restore(snap)
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, 6*time.Second, status.RemainingTime)
+	})
+
+	t.Run("paused session keeps its remaining time", func(t *testing.T) {
+		snap := snapshotOf(3*time.Second, true)
+
+		tm := newTestTimer(baseConfig)
+		tm.currentTime = tm.currentTime.Add(time.Hour)
+		tm.Restore(snap)
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StatePaused, status.State)
+		assert.Equal(t, 7*time.Second, status.RemainingTime)
+	})
+
+	t.Run("session that ended while down is completed", func(t *testing.T) {
+		snap := snapshotOf(3*time.Second, false)
+
+		tm := newTestTimer(baseConfig)
+		tm.currentTime = tm.currentTime.Add(time.Hour)
+		tm.Restore(snap)
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeShortBreak, status.SessionType)
+		assert.Equal(t, 1, status.PomoCycle)
+		assert.Equal(t, 5*time.Second, status.RemainingTime)
+	})
+
+	t.Run("stopped snapshot is ignored", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		tm.Restore(&state.Snapshot{State: ipc.StateStopped})
+		assert.Equal(t, ipc.StateStopped, tm.Status().State)
+	})
+}
+
+func TestTimerHistory(t *testing.T) {
+	baseConfig := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+	}
+
+	newJournaledTimer := func(t *testing.T) (*testTimer, *history.Journal) {
+		journal := history.NewJournal(filepath.Join(t.TempDir(), history.FileName))
+		tm := newTestTimer(baseConfig)
+		tm.SetJournal(journal)
+		return tm, journal
+	}
+
+	t.Run("completed sessions are recorded", func(t *testing.T) {
+		tm, journal := newJournaledTimer(t)
+		start := tm.currentTime
+		tm.Start(&ipc.StartArgs{})
+
+		tm.advanceTime(3 * time.Second)
+		tm.Pause()
+		tm.currentTime = tm.currentTime.Add(2 * time.Second)
+		tm.Resume()
+		tm.advanceTime(7 * time.Second) // work ends
+		tm.advanceTime(5 * time.Second) // break ends
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+
+		assert.Equal(t, history.Entry{
+			Type:    ipc.TypeWork,
+			Start:   start,
+			End:     start.Add(12 * time.Second),
+			Planned: 10 * time.Second,
+			Actual:  10 * time.Second,
+			Paused:  2 * time.Second,
+			Cycle:   1,
+			Outcome: history.OutcomeCompleted,
+		}, entries[0])
+		assert.Equal(t, ipc.TypeShortBreak, entries[1].Type)
+		assert.Equal(t, 5*time.Second, entries[1].Actual)
+		assert.Equal(t, history.OutcomeCompleted, entries[1].Outcome)
+	})
+
+	t.Run("stopping while paused records the partial session", func(t *testing.T) {
+		tm, journal := newJournaledTimer(t)
+		tm.Start(&ipc.StartArgs{})
+
+		tm.advanceTime(4 * time.Second)
+		tm.Pause()
+		tm.currentTime = tm.currentTime.Add(3 * time.Second)
+		tm.Stop()
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, history.OutcomeStopped, entries[0].Outcome)
+		assert.Equal(t, 4*time.Second, entries[0].Actual)
+		assert.Equal(t, 3*time.Second, entries[0].Paused)
+	})
+
+	t.Run("skipped sessions are recorded", func(t *testing.T) {
+		tm, journal := newJournaledTimer(t)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(4 * time.Second)
+		assert.NoError(t, tm.Skip(false))
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, history.OutcomeSkipped, entries[0].Outcome)
+		assert.Equal(t, 10*time.Second, entries[0].Planned)
+		assert.Equal(t, 4*time.Second, entries[0].Actual)
+	})
+
+	t.Run("task labels are recorded", func(t *testing.T) {
+		tm, journal := newJournaledTimer(t)
+		tm.Start(&ipc.StartArgs{Task: "review", Tags: []string{"team"}})
+		tm.advanceTime(10 * time.Second) // work
+
+		assert.NoError(t, tm.SetTask("docs", nil))
+		status := tm.Status()
+		assert.Equal(t, "docs", status.Task)
+		assert.Empty(t, status.Tags)
+		tm.advanceTime(5 * time.Second) // break
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "review", entries[0].Task)
+		assert.Equal(t, []string{"team"}, entries[0].Tags)
+		assert.Equal(t, "docs", entries[1].Task)
+		assert.Empty(t, entries[1].Tags)
+	})
+
+	t.Run("stopping an idle timer records nothing", func(t *testing.T) {
+		tm, journal := newJournaledTimer(t)
+		tm.Stop()
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestTimerExtend(t *testing.T) {
+	baseConfig := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+	}
+
+	t.Run("extend running session", func(t *testing.T) {
+		journal := history.NewJournal(filepath.Join(t.TempDir(), history.FileName))
+		tm := newTestTimer(baseConfig)
+		tm.SetJournal(journal)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(3 * time.Second)
+
+		reply, err := tm.Extend(5 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Second, reply.Applied)
+		assert.Equal(t, 12*time.Second, reply.RemainingTime)
+
+		tm.advanceTime(11 * time.Second)
+		assert.Equal(t, ipc.TypeWork, tm.Status().SessionType)
+		tm.advanceTime(time.Second)
+		assert.Equal(t, ipc.TypeShortBreak, tm.Status().SessionType)
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, 15*time.Second, entries[0].Planned)
+		assert.Equal(t, 15*time.Second, entries[0].Actual)
+	})
+
+	t.Run("shorten paused session", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(3 * time.Second)
+		tm.Pause()
+		tm.currentTime = tm.currentTime.Add(time.Minute)
+
+		reply, err := tm.Extend(-4 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 3*time.Second, reply.RemainingTime)
+		assert.Equal(t, 3*time.Second, tm.Status().RemainingTime)
+
+		tm.Resume()
+		tm.advanceTime(3 * time.Second)
+		assert.Equal(t, ipc.TypeShortBreak, tm.Status().SessionType)
+	})
+
+	t.Run("extension is limited by the maximum duration", func(t *testing.T) {
+		cfg := *baseConfig
+		cfg.Extend.MaxDuration = 15 * time.Second
+		tm := newTestTimer(&cfg)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(2 * time.Second)
+		tm.Pause()
+		tm.currentTime = tm.currentTime.Add(4 * time.Second)
+		tm.Resume()
+
+		reply, err := tm.Extend(time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Second, reply.Applied)
+		assert.Equal(t, 13*time.Second, reply.RemainingTime)
+	})
+
+	t.Run("shortening is limited by the minimum remaining time", func(t *testing.T) {
+		cfg := *baseConfig
+		cfg.Extend.MinRemaining = 2 * time.Second
+		tm := newTestTimer(&cfg)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(3 * time.Second)
+
+		reply, err := tm.Extend(-time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, -5*time.Second, reply.Applied)
+		assert.Equal(t, 2*time.Second, reply.RemainingTime)
+	})
+
+	t.Run("extend without a session", func(t *testing.T) {
+		tm := newTestTimer(baseConfig)
+		_, err := tm.Extend(time.Minute)
+		assert.ErrorIs(t, err, ErrNoSession)
+	})
+}
+
+func TestTimerManualTransition(t *testing.T) {
+	manual := true
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+		ManualTransition:   config.Manual{Work: &manual},
+	}
+
+	t.Run("finished work session waits for next", func(t *testing.T) {
+		journal := history.NewJournal(filepath.Join(t.TempDir(), history.FileName))
+		tm := newTestTimer(cfg)
+		tm.SetJournal(journal)
+		tm.Start(&ipc.StartArgs{})
+
+		tm.advanceTime(10 * time.Second)
+		tm.advanceTime(4 * time.Second)
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateDone, status.State)
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, time.Duration(0), status.RemainingTime)
+		assert.Equal(t, 4*time.Second, status.Overtime)
+
+		assert.NoError(t, tm.Next())
+		status = tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeShortBreak, status.SessionType)
+		assert.Equal(t, 5*time.Second, status.RemainingTime)
+
+		entries, err := journal.Read(history.Filter{})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, history.OutcomeCompleted, entries[0].Outcome)
+		assert.Equal(t, 10*time.Second, entries[0].Actual)
+		assert.Equal(t, 4*time.Second, entries[0].Overtime)
+	})
+
+	t.Run("breaks fall back to the default", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(10 * time.Second)
+		assert.NoError(t, tm.Next())
+
+		tm.advanceTime(5 * time.Second)
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, 2, status.PomoCycle)
+	})
+
+	t.Run("next while running", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		tm.Start(&ipc.StartArgs{})
+		assert.ErrorIs(t, tm.Next(), ErrNotDone)
+	})
+}
+
+func TestTimerSequence(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+		Sequences: map[string]config.Sequence{
+			"day": {
+				Steps: []config.Step{
+					{Name: "morning", Type: "work", Duration: 90 * time.Second},
+					{Type: "short_break", Duration: 20 * time.Second},
+					{Name: "lunch", Type: "long_break", Duration: 60 * time.Second},
+				},
+			},
+			"loop": {
+				Loop: true,
+				Steps: []config.Step{
+					{Type: "work", Duration: 3 * time.Second},
+					{Type: "short_break", Duration: 2 * time.Second},
+				},
+			},
+		},
+	}
+
+	t.Run("steps run in order and the sequence ends", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		assert.NoError(t, tm.Start(&ipc.StartArgs{Sequence: "day"}))
+
+		status := tm.Status()
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, "morning", status.Step)
+		assert.Equal(t, 1, status.StepIndex)
+		assert.Equal(t, 3, status.StepCount)
+		assert.Equal(t, 90*time.Second, status.RemainingTime)
+
+		tm.advanceTime(90 * time.Second)
+		status = tm.Status()
+		assert.Equal(t, ipc.TypeShortBreak, status.SessionType)
+		assert.Equal(t, "short_break", status.Step)
+		assert.Equal(t, 20*time.Second, status.RemainingTime)
+
+		tm.advanceTime(20 * time.Second)
+		status = tm.Status()
+		assert.Equal(t, ipc.TypeLongBreak, status.SessionType)
+		assert.Equal(t, "lunch", status.Step)
+
+		tm.advanceTime(60 * time.Second)
+		status = tm.Status()
+		assert.Equal(t, ipc.StateStopped, status.State)
+		assert.Empty(t, status.Sequence)
+		assert.Empty(t, status.Step)
+		assert.Zero(t, status.StepIndex)
+
+		// A plain start after the sequence ended runs the pomodoro cycle.
+		assert.NoError(t, tm.Start(&ipc.StartArgs{}))
+		status = tm.Status()
+		assert.Empty(t, status.Sequence)
+		assert.Equal(t, 10*time.Second, status.RemainingTime)
+	})
+
+	t.Run("looping sequence starts over with the next cycle", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		assert.NoError(t, tm.Start(&ipc.StartArgs{Sequence: "loop"}))
+
+		tm.advanceTime(3 * time.Second)
+		tm.advanceTime(2 * time.Second)
+
+		status := tm.Status()
+		assert.Equal(t, ipc.StateRunning, status.State)
+		assert.Equal(t, ipc.TypeWork, status.SessionType)
+		assert.Equal(t, 1, status.StepIndex)
+		assert.Equal(t, 2, status.PomoCycle)
+	})
+
+	t.Run("unknown sequence", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		assert.ErrorContains(t, tm.Start(&ipc.StartArgs{Sequence: "missing"}), "unknown sequence")
+		assert.Equal(t, ipc.StateStopped, tm.Status().State)
+	})
+}
+
+func TestTimerDailyGoal(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         4,
+		DailyGoal:          config.Goal{Pomodoros: 2},
+	}
+
+	t.Run("completed work sessions count toward the goal", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(10 * time.Second) // work
+		tm.advanceTime(5 * time.Second)  // break
+		tm.advanceTime(4 * time.Second)
+		assert.NoError(t, tm.Skip(false)) // skipped work only adds focus time
+
+		status := tm.Status()
+		assert.Equal(t, 1, status.TodayPomodoros)
+		assert.Equal(t, 14*time.Second, status.TodayFocus)
+		assert.Equal(t, 2, status.GoalPomodoros)
+		assert.False(t, tm.tally.GoalReached)
+
+		tm.advanceTime(5 * time.Second)  // break
+		tm.advanceTime(10 * time.Second) // work
+		assert.Equal(t, 2, tm.Status().TodayPomodoros)
+		assert.True(t, tm.tally.GoalReached)
+	})
+
+	t.Run("tally survives a restart and resets the next day", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(10 * time.Second)
+		tm.Stop()
+		snap := tm.snapshot()
+
+		restarted := newTestTimer(cfg)
+		restarted.Restore(snap)
+		assert.Equal(t, 1, restarted.Status().TodayPomodoros)
+
+		restarted.currentTime = restarted.currentTime.Add(24 * time.Hour)
+		assert.Equal(t, 0, restarted.Status().TodayPomodoros)
+	})
+}
+
+func TestTimerEvents(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+	}
+
+	t.Run("events are emitted in order", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		var events []ipc.EventType
+		tm.SetEventHandler(func(e ipc.Event) {
+			events = append(events, e.Type)
+		})
+
+		tm.Start(&ipc.StartArgs{})
+		tm.Pause()
+		tm.Resume()
+		tm.advanceTime(10 * time.Second)
+		assert.NoError(t, tm.Skip(false))
+		tm.Stop()
+
+		assert.Equal(t, []ipc.EventType{
+			ipc.EventStarted,
+			ipc.EventPaused,
+			ipc.EventResumed,
+			ipc.EventCompleted,
+			ipc.EventStarted,
+			ipc.EventSkipped,
+			ipc.EventStarted,
+			ipc.EventStopped,
+		}, events)
+	})
+
+	t.Run("skipping with hooks completes the session when it is skipped", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		notifier := &fakeNotifier{}
+		tm.SetNotifier(notifier)
+		start := tm.currentTime
+
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(4 * time.Second)
+		assert.NoError(t, tm.Skip(true))
+
+		// start, skip, complete and the start of the break
+		require.Eventually(t, func() bool { return notifier.count() == 4 }, time.Second, time.Millisecond)
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+		for _, m := range notifier.messages {
+			if m.Event == config.EventSkip || m.Event == config.EventComplete {
+				assert.Equal(t, start.Add(4*time.Second), m.Context.EndedAt, m.Event)
+				assert.Equal(t, 4*time.Second, m.Context.Elapsed, m.Event)
+			}
+		}
+	})
+}
+
+func TestTimerWarnings(t *testing.T) {
+	tm := newTestTimer(&config.Config{
+		WorkDuration:       25 * time.Minute,
+		ShortBreakDuration: 5 * time.Minute,
+		LongBreakDuration:  15 * time.Minute,
+		PomoCycles:         4,
+		Warnings: config.Warnings{
+			Default: []time.Duration{10 * time.Minute},
+			Work:    []time.Duration{2 * time.Minute, 30 * time.Second},
+		},
+	})
+	warnings := 0
+	tm.SetEventHandler(func(e ipc.Event) {
+		if e.Type == ipc.EventWarning {
+			warnings++
+		}
+	})
+
+	tm.Start(&ipc.StartArgs{})
+	tm.advanceTime(22*time.Minute + 59*time.Second)
+	assert.Equal(t, 0, warnings)
+	tm.advanceTime(time.Second)
+	assert.Equal(t, 1, warnings, "2m before the end")
+
+	// Pausing and resuming does not repeat the warning.
+	tm.Pause()
+	tm.advanceTime(5 * time.Minute)
+	tm.Resume()
+	tm.advanceTime(time.Second)
+	assert.Equal(t, 1, warnings)
+
+	tm.advanceTime(89 * time.Second)
+	assert.Equal(t, 2, warnings, "30s before the end")
+
+	// Extending the session gives the warnings that are ahead again.
+	_, err := tm.Extend(5 * time.Minute)
+	require.NoError(t, err)
+	tm.advanceTime(3*time.Minute + 30*time.Second)
+	assert.Equal(t, 3, warnings)
+	assert.Equal(t, []time.Duration{2 * time.Minute}, tm.snapshot().Warned)
+
+	// Short breaks use the default, which is not shorter than the session and never fires.
+	tm.advanceTime(2 * time.Minute)
+	assert.Equal(t, ipc.TypeShortBreak, tm.Status().SessionType)
+	tm.advanceTime(4 * time.Minute)
+	assert.Equal(t, 3, warnings)
+}
+
+// fakeNotifier records the messages it gets.
+type fakeNotifier struct {
+	mu       sync.Mutex
+	messages []notify.Message
+}
+
+func (f *fakeNotifier) Notify(m notify.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.messages = append(f.messages, m)
+	return nil
+}
+
+// texts returns the text of every message that has one.
+func (f *fakeNotifier) texts() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var texts []string
+	for _, m := range f.messages {
+		if m.Text != "" {
+			texts = append(texts, m.Text)
+		}
+	}
+	return texts
+}
+
+func (f *fakeNotifier) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.messages)
+}
+
+func TestTimerNotifications(t *testing.T) {
+	manual := true
+	tm := newTestTimer(&config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+		ManualTransition:   config.Manual{Work: &manual},
+		DailyGoal:          config.Goal{Pomodoros: 1},
+		Warnings:           config.Warnings{Work: []time.Duration{2 * time.Second}},
+	})
+	notifier := &fakeNotifier{}
+	tm.SetNotifier(notifier)
+
+	tm.Start(&ipc.StartArgs{})
+	tm.advanceTime(8 * time.Second)
+	tm.Pause()
+	tm.Resume()
+	tm.advanceTime(2 * time.Second)
+	require.NoError(t, tm.Next())
+
+	// start, warning, pause, resume, complete, goal_reached and start again
+	require.Eventually(t, func() bool { return notifier.count() == 7 }, time.Second, time.Millisecond)
+	assert.ElementsMatch(t, []string{
+		"Work session started.",
+		"2 seconds remaining.",
+		"Session complete.",
+		"Daily goal reached. Well done.",
+		"Time for a short break.",
+	}, notifier.texts())
+}
+
+func TestTimerSpokenMessages(t *testing.T) {
+	tm := newTestTimer(&config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+		TTS: config.TTS{Messages: map[string]string{
+			config.EventStart: "Focus on {{.Task}}.",
+			config.EventPause: "Paused after {{spell .Elapsed}}.",
+		}},
+	})
+	notifier := &fakeNotifier{}
+	tm.SetNotifier(notifier)
+
+	tm.Start(&ipc.StartArgs{Task: "docs"})
+	tm.advanceTime(2 * time.Second)
+	tm.Pause()
+
+	require.Eventually(t, func() bool { return notifier.count() == 2 }, time.Second, time.Millisecond)
+	assert.ElementsMatch(t, []string{"Focus on docs.", "Paused after 2 seconds."}, notifier.texts())
+}
+
+func TestTimerMute(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Minute,
+		ShortBreakDuration: 5 * time.Minute,
+		LongBreakDuration:  8 * time.Minute,
+		PomoCycles:         2,
+		// The test clock starts at midnight UTC.
+		QuietHours: config.QuietHours{Start: 22 * 60, End: 30},
+	}
+
+	t.Run("quiet hours", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		notifier := &fakeNotifier{}
+		tm.SetNotifier(notifier)
+
+		tm.Start(&ipc.StartArgs{})
+		assert.True(t, tm.Status().QuietHours)
+		tm.advanceTime(40 * time.Minute) // completes the work session late
+		assert.False(t, tm.Status().QuietHours)
+
+		// start, complete and the start of the break
+		require.Eventually(t, func() bool { return notifier.count() == 3 }, time.Second, time.Millisecond)
+		notifier.mu.Lock()
+		defer notifier.mu.Unlock()
+		var muted []string
+		for _, m := range notifier.messages {
+			if m.Muted {
+				muted = append(muted, m.Event)
+			}
+		}
+		assert.Equal(t, []string{config.EventStart}, muted, "only the start at 00:00")
+	})
+
+	t.Run("mute for a while", func(t *testing.T) {
+		tm := newTestTimer(&config.Config{WorkDuration: 10 * time.Minute, ShortBreakDuration: 5 * time.Minute, LongBreakDuration: 8 * time.Minute, PomoCycles: 2})
+		var events []ipc.EventType
+		tm.SetEventHandler(func(e ipc.Event) { events = append(events, e.Type) })
+
+		require.NoError(t, tm.Mute(15*time.Minute))
+		status := tm.Status()
+		assert.True(t, status.Muted)
+		assert.Equal(t, tm.currentTime.Add(15*time.Minute), status.MutedUntil)
+		assert.True(t, tm.silenced())
+
+		tm.currentTime = tm.currentTime.Add(15 * time.Minute)
+		assert.False(t, tm.Status().Muted, "expired")
+		assert.False(t, tm.silenced())
+
+		require.NoError(t, tm.Mute(0))
+		tm.currentTime = tm.currentTime.Add(24 * time.Hour)
+		assert.True(t, tm.Status().Muted, "until unmuted")
+
+		// The mute survives a restart even while the timer is stopped.
+		restarted := newTestTimer(cfg)
+		restarted.Restore(tm.snapshot())
+		assert.True(t, restarted.Status().Muted)
+
+		tm.Unmute()
+		assert.False(t, tm.Status().Muted)
+		assert.Equal(t, []ipc.EventType{ipc.EventMuted, ipc.EventMuted, ipc.EventUnmuted}, events)
+
+		assert.Error(t, tm.Mute(-time.Minute))
+	})
+}
+
+func TestTimerReload(t *testing.T) {
+	cfg := &config.Config{
+		WorkDuration:       10 * time.Second,
+		ShortBreakDuration: 5 * time.Second,
+		LongBreakDuration:  8 * time.Second,
+		PomoCycles:         2,
+		Sequences: map[string]config.Sequence{
+			"day": {Steps: []config.Step{{Type: "work", Duration: 10 * time.Second}, {Type: "short_break", Duration: 5 * time.Second}}},
+		},
+	}
+	reloaded := *cfg
+	reloaded.ShortBreakDuration = 7 * time.Second
+	reloaded.LongBreakDuration = 9 * time.Second
+	reloaded.Sequences = nil
+
+	t.Run("running session keeps its config", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		var events []ipc.EventType
+		tm.SetEventHandler(func(e ipc.Event) { events = append(events, e.Type) })
+		tm.Start(&ipc.StartArgs{})
+
+		applied, err := tm.Reload(&reloaded, false)
+		require.NoError(t, err)
+		assert.False(t, applied)
+		assert.Same(t, &reloaded, tm.Config())
+		assert.Contains(t, events, ipc.EventConfigReloaded)
+
+		tm.advanceTime(10 * time.Second)
+		assert.Equal(t, 5*time.Second, tm.Status().RemainingTime, "old short break")
+
+		tm.Stop()
+		tm.Start(&ipc.StartArgs{})
+		tm.advanceTime(10 * time.Second)
+		assert.Equal(t, 7*time.Second, tm.Status().RemainingTime, "new short break")
+	})
+
+	t.Run("apply now keeps start overrides", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		shortBreak := 3 * time.Second
+		tm.Start(&ipc.StartArgs{ShortBreakDuration: &shortBreak})
+
+		applied, err := tm.Reload(&reloaded, true)
+		require.NoError(t, err)
+		assert.True(t, applied)
+		assert.Equal(t, 3*time.Second, tm.sessionConfig.ShortBreakDuration)
+		assert.Equal(t, 9*time.Second, tm.sessionConfig.LongBreakDuration)
+		assert.Equal(t, 10*time.Second, tm.Status().RemainingTime, "current session is unchanged")
+	})
+
+	t.Run("apply fails when the sequence is gone", func(t *testing.T) {
+		tm := newTestTimer(cfg)
+		tm.Start(&ipc.StartArgs{Sequence: "day"})
+
+		_, err := tm.Reload(&reloaded, true)
+		assert.Error(t, err)
+		assert.Same(t, cfg, tm.Config(), "nothing changes")
+	})
+}
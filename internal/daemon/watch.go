@@ -0,0 +1,103 @@
+package daemon
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// watcherBuffer is the number of events queued for a watcher before new ones are dropped.
+const watcherBuffer = 32
+
+// Broadcaster fans timer events out to every connected watcher.
+type Broadcaster struct {
+	mu   sync.Mutex
+	subs map[chan ipc.Event]struct{}
+}
+
+// NewBroadcaster creates a new Broadcaster.
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subs: make(map[chan ipc.Event]struct{})}
+}
+
+// Subscribe registers a new watcher.
+// The returned function unregisters it and closes the channel.
+func (b *Broadcaster) Subscribe() (<-chan ipc.Event, func()) {
+	ch := make(chan ipc.Event, watcherBuffer)
+
+	b.mu.Lock()
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish sends the event to every watcher without blocking.
+// Watchers that fall behind miss the event rather than stalling the timer.
+func (b *Broadcaster) Publish(event ipc.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default:
+			slog.Warn("Dropping event for slow watcher", "event", event.Type)
+		}
+	}
+}
+
+// Serve accepts watchers on the listener until it is closed.
+// Each watcher first receives the current status, then every event as a JSON line.
+func (b *Broadcaster) Serve(listener net.Listener, current func() ipc.Event) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if _, ok := err.(*net.OpError); ok {
+				return
+			}
+			slog.Error("Failed to accept watcher", "error", err)
+			continue
+		}
+		go b.serveConn(conn, current())
+	}
+}
+
+// serveConn streams events to a single watcher until it disconnects.
+func (b *Broadcaster) serveConn(conn net.Conn, initial ipc.Event) {
+	events, unsubscribe := b.Subscribe()
+	defer func() {
+		unsubscribe()
+		if err := conn.Close(); err != nil {
+			slog.Debug("Failed to close watcher connection", "error", err)
+		}
+	}()
+
+	// Watchers never send anything; reading only tells us when they hang up.
+	go func() {
+		_, _ = io.Copy(io.Discard, conn)
+		unsubscribe()
+	}()
+
+	enc := json.NewEncoder(conn)
+	if err := enc.Encode(initial); err != nil {
+		return
+	}
+	for event := range events {
+		if err := enc.Encode(event); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+func TestBroadcasterServe(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), ipc.WatchSocketName)
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	b := NewBroadcaster()
+	go b.Serve(listener, func() ipc.Event {
+		return ipc.Event{Type: ipc.EventStatus, Status: ipc.StatusReply{State: ipc.StateStopped}}
+	})
+
+	conn, err := net.Dial("unix", socketPath)
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	reader := bufio.NewReader(conn)
+
+	readEvent := func() ipc.Event {
+		line, err := reader.ReadBytes('\n')
+		require.NoError(t, err)
+		var event ipc.Event
+		require.NoError(t, json.Unmarshal(line, &event))
+		return event
+	}
+
+	initial := readEvent()
+	assert.Equal(t, ipc.EventStatus, initial.Type)
+	assert.Equal(t, ipc.StateStopped, initial.Status.State)
+
+	b.Publish(ipc.Event{Type: ipc.EventStarted, Status: ipc.StatusReply{State: ipc.StateRunning, SessionType: ipc.TypeWork}})
+
+	started := readEvent()
+	assert.Equal(t, ipc.EventStarted, started.Type)
+	assert.Equal(t, ipc.StateRunning, started.Status.State)
+	assert.Equal(t, ipc.TypeWork, started.Status.SessionType)
+}
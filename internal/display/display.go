@@ -1,12 +1,19 @@
 package display
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log/slog"
 	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/tsuperis3112/pmdr/internal/history"
+	"github.com/tsuperis3112/pmdr/internal/hook"
 	"github.com/tsuperis3112/pmdr/internal/ipc"
+	"github.com/tsuperis3112/pmdr/internal/stats"
 )
 
 func formatDuration(d time.Duration) string {
@@ -17,6 +24,17 @@ func formatDuration(d time.Duration) string {
 	return fmt.Sprintf("%02d:%02d:%02d", h, m, s)
 }
 
+// formatMinutes formats a duration to the minute, e.g. "2h05m" or "45m".
+func formatMinutes(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	if h > 0 {
+		return fmt.Sprintf("%dh%02dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
 func formatSessionType(st ipc.SessionType) string {
 	switch st {
 	case ipc.TypeWork:
@@ -45,11 +63,60 @@ func formatState(s ipc.SessionState) string {
 	}
 }
 
+func formatTask(task string, tags []string) string {
+	var parts []string
+	if task != "" {
+		parts = append(parts, task)
+	}
+	for _, tag := range tags {
+		parts = append(parts, "#"+tag)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatGoal describes the progress toward the daily goal, e.g. "(5/8 today)".
+func formatGoal(reply *ipc.StatusReply) string {
+	var parts []string
+	if reply.GoalPomodoros > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d", reply.TodayPomodoros, reply.GoalPomodoros))
+	}
+	if reply.GoalFocus > 0 {
+		parts = append(parts, fmt.Sprintf("%s/%s focus", formatMinutes(reply.TodayFocus), formatMinutes(reply.GoalFocus)))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s today)", strings.Join(parts, ", "))
+}
+
+// formatMute describes why notifications are silent, if they are.
+func formatMute(reply *ipc.StatusReply) string {
+	switch {
+	case reply.Muted && !reply.MutedUntil.IsZero():
+		return fmt.Sprintf("[muted until %s]", reply.MutedUntil.Local().Format("15:04"))
+	case reply.Muted:
+		return "[muted]"
+	case reply.QuietHours:
+		return "[quiet hours]"
+	}
+	return ""
+}
+
 // Status formats and prints the status reply
 func Status(reply *ipc.StatusReply) {
+	slog.Info(FormatStatus(reply))
+}
+
+// FormatStatus formats the status reply as a single line.
+func FormatStatus(reply *ipc.StatusReply) string {
 	if reply.State == ipc.StateStopped {
-		slog.Info("Timer is stopped.")
-		return
+		parts := []string{"Timer is stopped."}
+		for _, s := range []string{formatGoal(reply), formatMute(reply)} {
+			if s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, " ")
 	}
 
 	var sb strings.Builder
@@ -58,15 +125,194 @@ func Status(reply *ipc.StatusReply) {
 	sb.WriteString(" ")
 	sb.WriteString(formatSessionType(reply.SessionType))
 	sb.WriteString(" ")
-	sb.WriteString(formatDuration(reply.RemainingTime))
 
-	if !reply.EndTime.IsZero() {
-		sb.WriteString(fmt.Sprintf(" (ends at %s)", reply.EndTime.Format("15:04:05")))
+	if reply.State == ipc.StateDone {
+		sb.WriteString(fmt.Sprintf("+%s overtime (ended at %s, run pmdr next to continue)", formatDuration(reply.Overtime), reply.EndTime.Format("15:04:05")))
+	} else {
+		sb.WriteString(formatDuration(reply.RemainingTime))
+
+		if !reply.EndTime.IsZero() {
+			sb.WriteString(fmt.Sprintf(" (ends at %s)", reply.EndTime.Format("15:04:05")))
+		}
 	}
 
 	if reply.SessionType == ipc.TypeWork {
 		sb.WriteString(fmt.Sprintf(" (Cycle %d)", reply.PomoCycle))
 	}
 
-	slog.Info(sb.String())
+	if reply.Sequence != "" {
+		sb.WriteString(fmt.Sprintf(" (%s %d/%d: %s)", reply.Sequence, reply.StepIndex, reply.StepCount, reply.Step))
+	}
+
+	if reply.Profile != "" {
+		sb.WriteString(fmt.Sprintf(" [%s]", reply.Profile))
+	}
+
+	if task := formatTask(reply.Task, reply.Tags); task != "" {
+		sb.WriteString(" ")
+		sb.WriteString(task)
+	}
+
+	if goal := formatGoal(reply); goal != "" {
+		sb.WriteString(" ")
+		sb.WriteString(goal)
+	}
+
+	if mute := formatMute(reply); mute != "" {
+		sb.WriteString(" ")
+		sb.WriteString(mute)
+	}
+
+	return sb.String()
+}
+
+// Event prints a timer event in the given format (text or json).
+func Event(w io.Writer, event *ipc.Event, format string) error {
+	switch format {
+	case "text":
+		_, err := fmt.Fprintf(w, "%s %s: %s\n", event.Time.Local().Format("15:04:05"), event.Type, FormatStatus(&event.Status))
+		return err
+	case "json":
+		return json.NewEncoder(w).Encode(event)
+	default:
+		return fmt.Errorf("unknown format %q (expected text or json)", format)
+	}
+}
+
+// History prints the history entries as a table.
+func History(w io.Writer, entries []history.Entry) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(w, "No sessions recorded.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "START\tEND\tTYPE\tCYCLE\tPLANNED\tACTUAL\tPAUSED\tOVERTIME\tOUTCOME\tTASK")
+	for _, e := range entries {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Start.Local().Format("2006-01-02 15:04"),
+			e.End.Local().Format("15:04"),
+			formatSessionType(e.Type),
+			e.Cycle,
+			formatDuration(e.Planned),
+			formatDuration(e.Actual),
+			formatDuration(e.Paused),
+			formatDuration(e.Overtime),
+			e.Outcome,
+			formatTask(e.Task, e.Tags),
+		)
+	}
+	_ = tw.Flush()
+}
+
+// HookLog prints hook executions as a table.
+// The error output of failed executions is printed below them.
+func HookLog(w io.Writer, records []hook.Record) {
+	if len(records) == 0 {
+		_, _ = fmt.Fprintln(w, "No hook executions recorded.")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "TIME\tEVENT\tEXIT\tDURATION\tCOMMAND")
+	for _, r := range records {
+		exit := fmt.Sprint(r.ExitCode)
+		switch {
+		case r.TimedOut:
+			exit = "timeout"
+		case r.Status != 0:
+			exit = fmt.Sprintf("HTTP %d", r.Status)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"),
+			r.Event,
+			exit,
+			r.Duration.Round(time.Millisecond),
+			r.Command,
+		)
+		if r.Failed() {
+			detail := r.Error
+			if r.Stderr != "" {
+				detail = r.Stderr
+			}
+			for _, line := range strings.Split(detail, "\n") {
+				_, _ = fmt.Fprintf(tw, "\t\t\t\t  %s\n", line)
+			}
+		}
+	}
+	_ = tw.Flush()
+}
+
+// statsRecord is the machine-readable form of a stats row. Durations are in seconds.
+type statsRecord struct {
+	Period         string  `json:"period"`
+	Pomodoros      int     `json:"pomodoros"`
+	WorkSessions   int     `json:"work_sessions"`
+	FocusSeconds   int64   `json:"focus_seconds"`
+	BreakSeconds   int64   `json:"break_seconds"`
+	AverageSeconds int64   `json:"average_session_seconds"`
+	CompletionRate float64 `json:"completion_rate"`
+}
+
+func newStatsRecord(r stats.Row) statsRecord {
+	return statsRecord{
+		Period:         r.Period,
+		Pomodoros:      r.Pomodoros,
+		WorkSessions:   r.WorkSessions,
+		FocusSeconds:   int64(r.Focus.Round(time.Second) / time.Second),
+		BreakSeconds:   int64(r.Break.Round(time.Second) / time.Second),
+		AverageSeconds: int64(r.AverageSession.Round(time.Second) / time.Second),
+		CompletionRate: r.CompletionRate,
+	}
+}
+
+// Stats prints the stats rows in the given format (table, json or csv).
+func Stats(w io.Writer, rows []stats.Row, format string) error {
+	switch format {
+	case "table":
+		if len(rows) == 0 {
+			_, err := fmt.Fprintln(w, "No sessions recorded.")
+			return err
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "PERIOD\tPOMODOROS\tFOCUS\tBREAK\tAVG SESSION\tCOMPLETION")
+		for _, r := range rows {
+			_, _ = fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%.0f%% (%d/%d)\n",
+				r.Period,
+				r.Pomodoros,
+				formatDuration(r.Focus),
+				formatDuration(r.Break),
+				formatDuration(r.AverageSession),
+				r.CompletionRate*100, r.Pomodoros, r.WorkSessions,
+			)
+		}
+		return tw.Flush()
+	case "json":
+		records := make([]statsRecord, 0, len(rows))
+		for _, r := range rows {
+			records = append(records, newStatsRecord(r))
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"period", "pomodoros", "work_sessions", "focus_seconds", "break_seconds", "average_session_seconds", "completion_rate"})
+		for _, r := range rows {
+			rec := newStatsRecord(r)
+			_ = cw.Write([]string{
+				rec.Period,
+				fmt.Sprint(rec.Pomodoros),
+				fmt.Sprint(rec.WorkSessions),
+				fmt.Sprint(rec.FocusSeconds),
+				fmt.Sprint(rec.BreakSeconds),
+				fmt.Sprint(rec.AverageSeconds),
+				fmt.Sprintf("%.4f", rec.CompletionRate),
+			})
+		}
+		cw.Flush()
+		return cw.Error()
+	default:
+		return fmt.Errorf("unknown format %q (expected table, json or csv)", format)
+	}
 }
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+	"github.com/tsuperis3112/pmdr/internal/jsonl"
+)
+
+// FileName is the name of the journal file inside the state directory.
+const FileName = "history.jsonl"
+
+// Outcome describes how a session ended.
+type Outcome string
+
+const (
+	OutcomeCompleted Outcome = "completed"
+	OutcomeStopped   Outcome = "stopped"
+	OutcomeSkipped   Outcome = "skipped"
+)
+
+// Entry is a single session recorded in the journal.
+// Durations are stored in nanoseconds.
+type Entry struct {
+	Type     ipc.SessionType `json:"type"`
+	Start    time.Time       `json:"start"`
+	End      time.Time       `json:"end"`
+	Planned  time.Duration   `json:"planned"`
+	Actual   time.Duration   `json:"actual"`
+	Paused   time.Duration   `json:"paused"`
+	Overtime time.Duration   `json:"overtime,omitempty"` // Time waited for a manual transition after End
+	Cycle    int             `json:"cycle"`
+	Outcome  Outcome         `json:"outcome"`
+	Step     string          `json:"step,omitempty"` // Name of the sequence step, if any
+	Task     string          `json:"task,omitempty"`
+	Tags     []string        `json:"tags,omitempty"`
+}
+
+// Filter selects entries when reading the journal.
+// Zero values disable the corresponding condition.
+type Filter struct {
+	Since time.Time // Entries that started at or after this time
+	Until time.Time // Entries that started before this time
+	Limit int       // Keep only the most recent entries
+}
+
+// Journal is an append-only log of sessions stored as JSON lines.
+type Journal struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewJournal creates a new Journal backed by the given file.
+func NewJournal(path string) *Journal {
+	return &Journal{path: path}
+}
+
+// DefaultPath returns the default location of the journal file.
+func DefaultPath() (string, error) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Path returns the path of the journal file.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Append writes an entry to the end of the journal.
+func (j *Journal) Append(e Entry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// The history is kept in full, since statistics are computed from it.
+	if err := jsonl.Append(j.path, e, 0); err != nil {
+		return fmt.Errorf("failed to write history entry: %w", err)
+	}
+	return nil
+}
+
+// Read returns the entries matching the filter in chronological order.
+// A missing journal is treated as empty.
+func (j *Journal) Read(f Filter) ([]Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries, err := jsonl.Read(j.path, func(e Entry) bool {
+		if !f.Since.IsZero() && e.Start.Before(f.Since) {
+			return false
+		}
+		return f.Until.IsZero() || e.Start.Before(f.Until)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history file: %w", err)
+	}
+	if f.Limit > 0 && len(entries) > f.Limit {
+		entries = entries[len(entries)-f.Limit:]
+	}
+	return entries, nil
+}
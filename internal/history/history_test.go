@@ -0,0 +1,83 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+func TestJournal(t *testing.T) {
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	entryAt := func(offset time.Duration, st ipc.SessionType) Entry {
+		return Entry{
+			Type:    st,
+			Start:   base.Add(offset),
+			End:     base.Add(offset + 25*time.Minute),
+			Planned: 25 * time.Minute,
+			Actual:  25 * time.Minute,
+			Cycle:   1,
+			Outcome: OutcomeCompleted,
+		}
+	}
+
+	journal := NewJournal(filepath.Join(t.TempDir(), "state", FileName))
+	entries := []Entry{
+		entryAt(0, ipc.TypeWork),
+		entryAt(time.Hour, ipc.TypeShortBreak),
+		entryAt(2*time.Hour, ipc.TypeWork),
+		entryAt(3*time.Hour, ipc.TypeLongBreak),
+	}
+	for _, e := range entries {
+		require.NoError(t, journal.Append(e))
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		expected []Entry
+	}{
+		{
+			name:     "no filter",
+			filter:   Filter{},
+			expected: entries,
+		},
+		{
+			name:     "since",
+			filter:   Filter{Since: base.Add(time.Hour)},
+			expected: entries[1:],
+		},
+		{
+			name:     "until",
+			filter:   Filter{Until: base.Add(2 * time.Hour)},
+			expected: entries[:2],
+		},
+		{
+			name:     "limit keeps the most recent entries",
+			filter:   Filter{Limit: 2},
+			expected: entries[2:],
+		},
+		{
+			name:     "combined",
+			filter:   Filter{Since: base.Add(time.Hour), Until: base.Add(3 * time.Hour), Limit: 1},
+			expected: entries[2:3],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := journal.Read(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+
+	t.Run("missing journal is empty", func(t *testing.T) {
+		got, err := NewJournal(filepath.Join(t.TempDir(), FileName)).Read(Filter{})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
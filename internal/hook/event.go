@@ -0,0 +1,93 @@
+package hook
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event describes what triggered a hook.
+// Zero values mean the field does not apply, e.g. EndedAt for a start event.
+type Event struct {
+	Event       string        // Hook event, e.g. "complete"
+	SessionType string        // work, short_break or long_break
+	Cycle       int           // Pomodoro cycle of the session
+	StartedAt   time.Time     // When the session started
+	EndedAt     time.Time     // When the session ended
+	Duration    time.Duration // Planned length of the session, excluding pauses
+	Elapsed     time.Duration // Time spent in the session so far, excluding pauses
+	Remaining   time.Duration // Time left in the session
+	Step        string        // Name of the sequence step, if any
+	Task        string
+	Tags        []string
+}
+
+// document is the JSON form of an Event. Durations are in seconds.
+type document struct {
+	Event       string     `json:"event"`
+	SessionType string     `json:"session_type,omitempty"`
+	Cycle       int        `json:"cycle"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Duration    float64    `json:"duration,omitempty"`
+	Elapsed     float64    `json:"elapsed,omitempty"`
+	Remaining   float64    `json:"remaining,omitempty"`
+	Step        string     `json:"step,omitempty"`
+	Task        string     `json:"task,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (e Event) MarshalJSON() ([]byte, error) {
+	doc := document{
+		Event:       e.Event,
+		SessionType: e.SessionType,
+		Cycle:       e.Cycle,
+		Duration:    e.Duration.Seconds(),
+		Elapsed:     e.Elapsed.Seconds(),
+		Remaining:   e.Remaining.Seconds(),
+		Step:        e.Step,
+		Task:        e.Task,
+		Tags:        e.Tags,
+	}
+	if !e.StartedAt.IsZero() {
+		doc.StartedAt = &e.StartedAt
+	}
+	if !e.EndedAt.IsZero() {
+		doc.EndedAt = &e.EndedAt
+	}
+	return json.Marshal(doc)
+}
+
+// Env returns the event as environment variables in "KEY=value" form.
+// Times are in RFC 3339 form and durations in whole seconds; fields that do not apply are empty.
+func (e Event) Env() []string {
+	return []string{
+		"PMDR_EVENT=" + e.Event,
+		"PMDR_SESSION_TYPE=" + e.SessionType,
+		"PMDR_CYCLE=" + strconv.Itoa(e.Cycle),
+		"PMDR_STARTED_AT=" + formatTime(e.StartedAt),
+		"PMDR_ENDED_AT=" + formatTime(e.EndedAt),
+		"PMDR_DURATION=" + formatSeconds(e.Duration),
+		"PMDR_ELAPSED=" + formatSeconds(e.Elapsed),
+		"PMDR_REMAINING=" + formatSeconds(e.Remaining),
+		"PMDR_STEP=" + e.Step,
+		"PMDR_TASK=" + e.Task,
+		"PMDR_TAGS=" + strings.Join(e.Tags, ","),
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatSeconds(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return strconv.FormatInt(int64(d.Round(time.Second)/time.Second), 10)
+}
@@ -0,0 +1,19 @@
+package hook
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// Templates are checked against tmpl.Fields, so it must list exactly the fields of Event.
+func TestEventTemplateFields(t *testing.T) {
+	var fields []string
+	typ := reflect.TypeFor[Event]()
+	for i := range typ.NumField() {
+		fields = append(fields, typ.Field(i).Name)
+	}
+	assert.Equal(t, tmpl.Fields, fields)
+}
@@ -1,27 +1,171 @@
 package hook
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
 	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
 )
 
-// Run executes the given commands in the background.
-func Run(commands []string) {
+// maxOutput is the number of bytes of stdout and stderr kept from each command.
+const maxOutput = 64 * 1024
+
+// waitDelay is how long to wait for the output of a killed command,
+// in case a process it started keeps stdout or stderr open.
+const waitDelay = time.Second
+
+// Runner runs hook commands.
+type Runner struct {
+	Timeout     time.Duration // Default time limit of a command; 0 disables it
+	OutputLevel slog.Level    // Log level of the captured output
+	Log         *Log          // Records every execution when set
+}
+
+// Run runs the given hooks concurrently and waits for all of them to finish.
+// Commands are rendered as templates with the event first. They get the event as PMDR_* environment variables,
+// and as a JSON document on stdin when they set stdin: json; webhooks get the JSON document as the request body unless they set a body template.
+func (r Runner) Run(commands []config.HookCommand, event Event) {
 	if len(commands) == 0 {
 		return
 	}
 
-	for _, cmdStr := range commands {
-		go func(c string) {
-			cmd := exec.Command("sh", "-c", c)
-			if err := cmd.Start(); err != nil {
-				slog.Error("Failed to start hook command", "error", err, "command", c)
-			}
+	doc, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Failed to encode hook event", "error", err, "event", event.Event)
+	}
+	env := append(os.Environ(), event.Env()...)
+
+	var wg sync.WaitGroup
+	for _, c := range commands {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.run(c, event, env, doc)
+		}()
+	}
+	wg.Wait()
+}
+
+// run runs a single hook within its timeout and records the result.
+func (r Runner) run(c config.HookCommand, event Event, env []string, doc []byte) {
+	timeout := r.Timeout
+	if c.Timeout > 0 {
+		timeout = c.Timeout
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	rec := Record{Time: time.Now(), Event: event.Event, Command: c.String()}
+	if c.Type == config.HookWebhook {
+		r.runWebhook(ctx, c, event, doc, &rec)
+	} else {
+		r.runCommand(ctx, c, event, env, doc, &rec)
+	}
+	rec.Duration = time.Since(rec.Time)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		rec.TimedOut = true
+		rec.Error = "timed out after " + timeout.String()
+	}
+
+	attrs := []any{"command", rec.Command, "event", rec.Event, "exit_code", rec.ExitCode, "duration", rec.Duration}
+	if rec.Status != 0 {
+		attrs = append(attrs, "status", rec.Status)
+	}
+	if rec.Failed() {
+		slog.Warn("Hook command failed", append(attrs, "error", rec.Error)...)
+	} else {
+		slog.Info("Executed hook command", attrs...)
+	}
+	r.record(rec)
+}
+
+// runCommand runs a shell command and logs its output.
+func (r Runner) runCommand(ctx context.Context, c config.HookCommand, event Event, env []string, doc []byte, rec *Record) {
+	command, err := tmpl.Render(c.Command, event)
+	if err != nil {
+		rec.ExitCode = -1
+		rec.Error = fmt.Sprintf("failed to render command: %v", err)
+		return
+	}
+
+	var stdout, stderr limitedBuffer
+	cmd := Command(ctx, command)
+	cmd.Env = env
+	// Other commands read an empty stdin, so that commands such as cat do not wait for input.
+	if c.Stdin == config.HookStdinJSON {
+		cmd.Stdin = bytes.NewReader(doc)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		rec.ExitCode = -1
+		rec.Error = err.Error()
+		return
+	}
+
+	err = cmd.Wait()
+	rec.ExitCode = cmd.ProcessState.ExitCode()
+	if err != nil {
+		rec.Error = err.Error()
+	}
+
+	if out := strings.TrimSpace(stdout.String()); out != "" {
+		slog.Log(ctx, r.OutputLevel, "Hook command output", "command", c.Command, "stdout", out)
+	}
+	errOut := strings.TrimSpace(stderr.String())
+	if errOut != "" {
+		slog.Log(ctx, r.OutputLevel, "Hook command output", "command", c.Command, "stderr", errOut)
+	}
+	if err != nil {
+		// Keep the error output of failures so "pmdr hooks log" can show why they failed.
+		rec.Stderr = errOut
+	}
+}
+
+// Command returns a command that runs the command line with sh -c.
+// When ctx is done, the whole process group is killed so that children of the shell do not outlive it.
+func Command(ctx context.Context, command string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.WaitDelay = waitDelay
+	setProcessGroup(cmd)
+	cmd.Cancel = func() error { return killProcessGroup(cmd) }
+	return cmd
+}
+
+// record appends the record to the log, if any.
+func (r Runner) record(rec Record) {
+	if r.Log == nil {
+		return
+	}
+	if err := r.Log.Append(rec); err != nil {
+		slog.Error("Failed to record hook execution", "error", err)
+	}
+}
+
+// limitedBuffer keeps the first maxOutput bytes written to it and discards the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+}
 
-			// We don't wait for the command to finish, but we should release resources.
-			// The goroutine will exit after this, and the OS will handle the child process.
-			_ = cmd.Wait() // This is to avoid zombie processes
-			slog.Info("Executed hook command", "command", c, "pid", cmd.Process.Pid)
-		}(cmdStr)
+// Write implements io.Writer. It never fails so the command is not stopped by a full buffer.
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := maxOutput - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
 	}
+	return len(p), nil
 }
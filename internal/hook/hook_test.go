@@ -8,6 +8,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
 )
 
 func TestRunHooks(t *testing.T) {
@@ -39,15 +41,12 @@ func TestRunHooks(t *testing.T) {
 			testFile := filepath.Join(tempDir, "test_output.txt")
 
 			// Create commands that append to the test file
-			var fileCommands []string
+			var fileCommands []config.HookCommand
 			for _, cmd := range tt.commands {
-				fileCommands = append(fileCommands, fmt.Sprintf("%s >> %s", cmd, testFile))
+				fileCommands = append(fileCommands, config.HookCommand{Command: fmt.Sprintf("%s >> %s", cmd, testFile)})
 			}
 
-			Run(fileCommands)
-
-			// Give the goroutines a moment to execute and write to the file
-			time.Sleep(100 * time.Millisecond)
+			Runner{}.Run(fileCommands, Event{Event: "complete"})
 
 			if len(tt.expected) == 0 {
 				// If no commands were run, the file should not be created
@@ -69,3 +68,150 @@ func TestRunHooks(t *testing.T) {
 		})
 	}
 }
+
+func TestRunHooksEnv(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test_output.txt")
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	Runner{}.Run([]config.HookCommand{
+		{Command: fmt.Sprintf("echo \"$PMDR_EVENT $PMDR_SESSION_TYPE $PMDR_CYCLE $PMDR_STARTED_AT [$PMDR_ENDED_AT] $PMDR_DURATION $PMDR_TASK $PMDR_TAGS\" >> %s", testFile)},
+	}, Event{
+		Event:       "start",
+		SessionType: "work",
+		Cycle:       2,
+		StartedAt:   start,
+		Duration:    25 * time.Minute,
+		Task:        "write docs",
+		Tags:        []string{"docs", "pmdr"},
+	})
+
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test output file: %v", err)
+	}
+	assert.Equal(t, "start work 2 2025-06-02T09:00:00Z [] 1500 write docs docs,pmdr\n", string(content))
+}
+
+func TestRunHooksStdin(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test_output.txt")
+	start := time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC)
+
+	emptyFile := filepath.Join(t.TempDir(), "empty.txt")
+	Runner{}.Run([]config.HookCommand{
+		{Command: fmt.Sprintf("cat >> %s", testFile), Stdin: config.HookStdinJSON},
+		{Command: fmt.Sprintf("cat >> %s", emptyFile)},
+	}, Event{
+		Event:       "complete",
+		SessionType: "short_break",
+		Cycle:       1,
+		StartedAt:   start,
+		EndedAt:     start.Add(5 * time.Minute),
+		Duration:    5 * time.Minute,
+	})
+
+	content, err := os.ReadFile(testFile)
+	if err != nil {
+		t.Fatalf("Failed to read test output file: %v", err)
+	}
+	assert.JSONEq(t, `{
+		"event": "complete",
+		"session_type": "short_break",
+		"cycle": 1,
+		"started_at": "2025-06-02T09:00:00Z",
+		"ended_at": "2025-06-02T09:05:00Z",
+		"duration": 300
+	}`, string(content))
+
+	// Without stdin: json, stdin is empty.
+	content, err = os.ReadFile(emptyFile)
+	require.NoError(t, err)
+	assert.Empty(t, content)
+}
+
+func TestRunHooksLog(t *testing.T) {
+	log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+	runner := Runner{Timeout: time.Minute, Log: log}
+
+	start := time.Now()
+	runner.Run([]config.HookCommand{
+		{Command: "echo ok"},
+		{Command: "echo broken >&2; exit 3"},
+		{Command: "sleep 5 & sleep 5", Timeout: 100 * time.Millisecond},
+	}, Event{Event: "start"})
+	// The timeout kills the whole process group instead of waiting for the background sleep.
+	assert.Less(t, time.Since(start), 3*time.Second)
+
+	records, err := log.Read(0, false)
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	byCommand := make(map[string]Record)
+	for _, r := range records {
+		assert.Equal(t, "start", r.Event)
+		byCommand[r.Command] = r
+	}
+
+	ok := byCommand["echo ok"]
+	assert.Equal(t, 0, ok.ExitCode)
+	assert.False(t, ok.Failed())
+	assert.Empty(t, ok.Stderr)
+
+	broken := byCommand["echo broken >&2; exit 3"]
+	assert.Equal(t, 3, broken.ExitCode)
+	assert.True(t, broken.Failed())
+	assert.Equal(t, "broken", broken.Stderr)
+
+	slow := byCommand["sleep 5 & sleep 5"]
+	assert.True(t, slow.TimedOut)
+	assert.True(t, slow.Failed())
+	assert.Equal(t, "timed out after 100ms", slow.Error)
+
+	failed, err := log.Read(1, true)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.True(t, failed[0].Failed())
+}
+
+func TestLogRotation(t *testing.T) {
+	defer func(size int64) { MaxLogSize = size }(MaxLogSize)
+	MaxLogSize = 200
+
+	dir := t.TempDir()
+	log := NewLog(filepath.Join(dir, LogFileName))
+	for i := range 10 {
+		require.NoError(t, log.Append(Record{Event: "start", Command: fmt.Sprintf("echo %d", i)}))
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, LogFileName+"*"))
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "older records are dropped with the previous rotated log")
+
+	records, err := log.Read(0, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	assert.Less(t, len(records), 10)
+	assert.Equal(t, "echo 9", records[len(records)-1].Command)
+	last, err := log.Read(2, false)
+	require.NoError(t, err)
+	assert.Equal(t, records[len(records)-2:], last)
+}
+
+func TestRunHooksTemplate(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "test_output.txt")
+	log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+
+	Runner{Log: log}.Run([]config.HookCommand{
+		{Command: fmt.Sprintf("echo '{{.SessionType}} done after {{duration .Elapsed}}' >> %s", testFile)},
+		{Command: "echo {{.Missing}}"},
+	}, Event{Event: "complete", SessionType: "work", Elapsed: 25 * time.Minute})
+
+	content, err := os.ReadFile(testFile)
+	require.NoError(t, err)
+	assert.Equal(t, "work done after 25m\n", string(content))
+
+	failed, err := log.Read(0, true)
+	require.NoError(t, err)
+	require.Len(t, failed, 1)
+	assert.Equal(t, "echo {{.Missing}}", failed[0].Command)
+	assert.Contains(t, failed[0].Error, "failed to render command")
+}
@@ -0,0 +1,89 @@
+package hook
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/jsonl"
+)
+
+// LogFileName is the name of the hook log inside the state directory.
+const LogFileName = "hooks.jsonl"
+
+// MaxLogSize is the size at which the hook log is rotated. The previous log is kept
+// next to it with a ".1" suffix, so the hook log takes at most about twice this size.
+var MaxLogSize int64 = 1024 * 1024
+
+// Record is a single execution of a hook command.
+type Record struct {
+	Time     time.Time     `json:"time"`
+	Event    string        `json:"event"`
+	Command  string        `json:"command"`
+	Duration time.Duration `json:"duration"`
+	ExitCode int           `json:"exit_code"`        // -1 when the command could not be started or was killed
+	Status   int           `json:"status,omitempty"` // HTTP status returned to a webhook
+	TimedOut bool          `json:"timed_out,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Stderr   string        `json:"stderr,omitempty"`
+}
+
+// Failed reports whether the command did not run to a successful exit.
+func (r Record) Failed() bool {
+	return r.ExitCode != 0 || r.TimedOut || r.Error != ""
+}
+
+// Log is a log of hook executions stored as JSON lines.
+type Log struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewLog creates a new Log backed by the given file.
+func NewLog(path string) *Log {
+	return &Log{path: path}
+}
+
+// DefaultLogPath returns the default location of the hook log.
+func DefaultLogPath() (string, error) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, LogFileName), nil
+}
+
+// Path returns the path of the log file.
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append writes a record to the end of the log, rotating the log once it reaches MaxLogSize.
+func (l *Log) Append(r Record) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := jsonl.Append(l.path, r, MaxLogSize); err != nil {
+		return fmt.Errorf("failed to write hook record: %w", err)
+	}
+	return nil
+}
+
+// Read returns the most recent records in chronological order, including those of the rotated log.
+// A limit of 0 returns every record; failedOnly keeps only failed executions.
+// A missing log is treated as empty.
+func (l *Log) Read(limit int, failedOnly bool) ([]Record, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records, err := jsonl.Read(l.path, func(r Record) bool { return !failedOnly || r.Failed() })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hook log: %w", err)
+	}
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+	return records, nil
+}
@@ -0,0 +1,95 @@
+package hook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// retryDelay is the wait before the first retry of a webhook. It doubles with every attempt.
+var retryDelay = time.Second
+
+// runWebhook sends the event to a webhook, retrying on network errors and server errors.
+func (r Runner) runWebhook(ctx context.Context, c config.HookCommand, event Event, doc []byte, rec *Record) {
+	body := doc
+	if c.BodyTemplate != "" {
+		rendered, err := tmpl.Render(c.BodyTemplate, event)
+		if err != nil {
+			rec.ExitCode = -1
+			rec.Error = fmt.Sprintf("failed to render body template: %v", err)
+			return
+		}
+		body = []byte(rendered)
+	}
+
+	status, err := Post(ctx, c, body)
+	rec.Status = status
+	if err != nil {
+		rec.ExitCode = -1
+		rec.Error = err.Error()
+		return
+	}
+	rec.ExitCode = 0
+}
+
+// Post sends the body to the webhook of c, retrying up to c.Retries times on network errors and server errors.
+// It returns the status of the last response, or 0 if none was received.
+func Post(ctx context.Context, c config.HookCommand, body []byte) (int, error) {
+	delay := retryDelay
+	for attempt := 0; ; attempt++ {
+		status, err := post(ctx, c, body)
+		if err == nil || attempt >= c.Retries || !retryable(status) {
+			return status, err
+		}
+		slog.Debug("Retrying webhook", "url", c.URL, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return status, err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// post sends a single request and returns the response status.
+func post(ctx context.Context, c config.HookCommand, body []byte) (int, error) {
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", config.ProjectName)
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxOutput))
+
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryable reports whether a request that failed with the status is worth retrying.
+// A status of 0 means no response was received.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
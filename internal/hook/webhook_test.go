@@ -0,0 +1,139 @@
+package hook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+func TestRunWebhook(t *testing.T) {
+	retryDelay = time.Millisecond
+	event := Event{Event: "complete", SessionType: "work", Cycle: 1, Task: "write docs"}
+
+	t.Run("posts the event", func(t *testing.T) {
+		var method, contentType, token string
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			contentType = r.Header.Get("Content-Type")
+			token = r.Header.Get("Authorization")
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+		Runner{Log: log}.Run([]config.HookCommand{{
+			Type:    config.HookWebhook,
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "Bearer secret"},
+		}}, event)
+
+		assert.Equal(t, http.MethodPost, method)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, "Bearer secret", token)
+		assert.JSONEq(t, `{"event":"complete","session_type":"work","cycle":1,"task":"write docs"}`, string(body))
+
+		records, err := log.Read(0, false)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.Equal(t, "POST "+server.URL, records[0].Command)
+		assert.Equal(t, http.StatusOK, records[0].Status)
+		assert.False(t, records[0].Failed())
+	})
+
+	t.Run("body template", func(t *testing.T) {
+		var method string
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			body, _ = io.ReadAll(r.Body)
+		}))
+		defer server.Close()
+
+		Runner{}.Run([]config.HookCommand{{
+			Type:         config.HookWebhook,
+			URL:          server.URL,
+			Method:       http.MethodPut,
+			BodyTemplate: `{"status": {{json (printf "%s finished: %s" .SessionType .Task)}}}`,
+		}}, event)
+
+		assert.Equal(t, http.MethodPut, method)
+		assert.Equal(t, `{"status": "work finished: write docs"}`, string(body))
+
+		quoted := event
+		quoted.Task = `fix "quotes" in C:\docs`
+		Runner{}.Run([]config.HookCommand{{
+			Type:         config.HookWebhook,
+			URL:          server.URL,
+			BodyTemplate: `{"text": {{json (printf "Focusing on %s" .Task)}}}`,
+		}}, quoted)
+
+		var doc map[string]string
+		require.NoError(t, json.Unmarshal(body, &doc), string(body))
+		assert.Equal(t, `Focusing on fix "quotes" in C:\docs`, doc["text"])
+	})
+
+	t.Run("retries server errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+		Runner{Log: log}.Run([]config.HookCommand{{Type: config.HookWebhook, URL: server.URL, Retries: 2}}, event)
+
+		assert.Equal(t, int32(3), calls.Load())
+		records, err := log.Read(0, false)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.False(t, records[0].Failed())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+		Runner{Log: log}.Run([]config.HookCommand{{Type: config.HookWebhook, URL: server.URL, Retries: 2}}, event)
+
+		assert.Equal(t, int32(1), calls.Load())
+		records, err := log.Read(0, false)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.True(t, records[0].Failed())
+		assert.Equal(t, http.StatusBadRequest, records[0].Status)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer server.Close()
+		defer close(release)
+
+		log := NewLog(filepath.Join(t.TempDir(), LogFileName))
+		Runner{Timeout: 50 * time.Millisecond, Log: log}.Run([]config.HookCommand{{Type: config.HookWebhook, URL: server.URL, Retries: 2}}, event)
+
+		records, err := log.Read(0, false)
+		require.NoError(t, err)
+		require.Len(t, records, 1)
+		assert.True(t, records[0].TimedOut)
+	})
+}
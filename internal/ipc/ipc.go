@@ -14,6 +14,8 @@ const (
 	SocketName = "pmdr.sock"
 	// PidFileName is the name of the pid file.
 	PidFileName = "pmdr.pid"
+	// WatchSocketName is the name of the socket that streams timer events.
+	WatchSocketName = "pmdr-watch.sock"
 )
 
 // SessionState represents the state of the timer.
@@ -27,6 +29,41 @@ const (
 	StateStopped // Idle
 )
 
+// sessionStateNames maps each state to the name used in JSON documents.
+var sessionStateNames = map[SessionState]string{
+	StateRunning: "running",
+	StatePaused:  "paused",
+	StateDone:    "done",
+	StateStopped: "stopped",
+}
+
+// String returns the name of the state (e.g. "running").
+func (s SessionState) String() string {
+	if name, ok := sessionStateNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("SessionState(%d)", int(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s SessionState) MarshalText() ([]byte, error) {
+	if _, ok := sessionStateNames[s]; !ok {
+		return nil, fmt.Errorf("unknown session state %d", int(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *SessionState) UnmarshalText(text []byte) error {
+	for state, name := range sessionStateNames {
+		if name == string(text) {
+			*s = state
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown session state %q", text)
+}
+
 // SessionType represents the type of the session.
 // NOTE: It is better to use stringer
 type SessionType int
@@ -37,6 +74,49 @@ const (
 	TypeLongBreak
 )
 
+// sessionTypeNames maps each session type to the name used in config files and journals.
+var sessionTypeNames = map[SessionType]string{
+	TypeWork:       "work",
+	TypeShortBreak: "short_break",
+	TypeLongBreak:  "long_break",
+}
+
+// String returns the name of the session type as used in config files (e.g. "short_break").
+func (st SessionType) String() string {
+	if name, ok := sessionTypeNames[st]; ok {
+		return name
+	}
+	return fmt.Sprintf("SessionType(%d)", int(st))
+}
+
+// ParseSessionType returns the session type with the given name.
+func ParseSessionType(name string) (SessionType, error) {
+	for st, n := range sessionTypeNames {
+		if n == name {
+			return st, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown session type %q", name)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (st SessionType) MarshalText() ([]byte, error) {
+	if _, ok := sessionTypeNames[st]; !ok {
+		return nil, fmt.Errorf("unknown session type %d", int(st))
+	}
+	return []byte(st.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (st *SessionType) UnmarshalText(text []byte) error {
+	parsed, err := ParseSessionType(string(text))
+	if err != nil {
+		return err
+	}
+	*st = parsed
+	return nil
+}
+
 // StartArgs holds the arguments for the Start RPC call.
 // Pointers are used to distinguish between a zero value and a value that was not set.
 type StartArgs struct {
@@ -44,20 +124,109 @@ type StartArgs struct {
 	ShortBreakDuration *time.Duration
 	LongBreakDuration  *time.Duration
 	PomoCycles         *int
+	Profile            string // Named profile from the config; empty selects the default profile
+	Sequence           string // Named sequence from the config; empty selects the configured one
+	Task               string
+	Tags               []string
+}
+
+// TaskArgs holds the arguments for the SetTask RPC call.
+// An empty task and no tags clear the current labels.
+type TaskArgs struct {
+	Task string
+	Tags []string
+}
+
+// SkipArgs holds the arguments for the Skip RPC call.
+type SkipArgs struct {
+	RunHooks bool // Run the completion hooks of the skipped session
+}
+
+// ExtendArgs holds the arguments for the Extend RPC call.
+type ExtendArgs struct {
+	Duration time.Duration // Negative values shorten the session
+}
+
+// ExtendReply holds the response for the Extend RPC call.
+type ExtendReply struct {
+	Applied       time.Duration // Requested duration after applying the configured bounds
+	RemainingTime time.Duration
+	EndTime       time.Time
+}
+
+// ReloadArgs holds the arguments for the Reload RPC call.
+type ReloadArgs struct {
+	Apply bool // Apply the new configuration to the running session too
+}
+
+// ReloadReply holds the response for the Reload RPC call.
+type ReloadReply struct {
+	Changed []string // Dotted keys of the settings that changed
+	Applied bool     // Whether the running session uses the new configuration
+	Session bool     // Whether a session was in progress
+}
+
+// MuteArgs holds the arguments for the Mute RPC call.
+type MuteArgs struct {
+	Duration time.Duration // Zero mutes until the Unmute call
+}
+
+// EventType identifies what happened to the timer.
+type EventType string
+
+const (
+	EventStatus         EventType = "status" // Sent once when a watcher connects
+	EventStarted        EventType = "started"
+	EventCompleted      EventType = "completed"
+	EventPaused         EventType = "paused"
+	EventResumed        EventType = "resumed"
+	EventStopped        EventType = "stopped"
+	EventSkipped        EventType = "skipped"
+	EventExtended       EventType = "extended"
+	EventTaskChanged    EventType = "task_changed"
+	EventWarning        EventType = "warning"
+	EventGoalReached    EventType = "goal_reached"
+	EventMuted          EventType = "muted"
+	EventUnmuted        EventType = "unmuted"
+	EventConfigReloaded EventType = "config_reloaded"
+)
+
+// Event is pushed to watchers as a JSON line whenever the timer changes.
+// Status holds the full status right after the event.
+type Event struct {
+	Type   EventType   `json:"type"`
+	Time   time.Time   `json:"time"`
+	Status StatusReply `json:"status"`
 }
 
 // Args holds arguments for RPC calls that don't need any.
 type Args struct{}
 
 // StatusReply holds the response for the status RPC call.
-
-// StatusReply holds the response for the status RPC call.
+// Durations are in nanoseconds when encoded as JSON.
 type StatusReply struct {
-	State         SessionState
-	SessionType   SessionType
-	RemainingTime time.Duration
-	EndTime       time.Time
-	PomoCycle     int
+	State         SessionState  `json:"state"`
+	SessionType   SessionType   `json:"session_type"`
+	RemainingTime time.Duration `json:"remaining_time"`
+	EndTime       time.Time     `json:"end_time"`
+	PomoCycle     int           `json:"pomo_cycle"`
+	Overtime      time.Duration `json:"overtime"` // Time since the session ended while waiting in StateDone
+	Profile       string        `json:"profile,omitempty"`
+	Sequence      string        `json:"sequence,omitempty"`
+	Step          string        `json:"step,omitempty"`       // Name of the current sequence step
+	StepIndex     int           `json:"step_index,omitempty"` // 1-based position of the current step in the sequence
+	StepCount     int           `json:"step_count,omitempty"`
+	Task          string        `json:"task,omitempty"`
+	Tags          []string      `json:"tags,omitempty"`
+	Muted         bool          `json:"muted"`
+	MutedUntil    time.Time     `json:"muted_until,omitzero"` // Zero while muted means until unmuted
+	QuietHours    bool          `json:"quiet_hours"`          // Whether the configured quiet hours are in effect
+
+	// Progress toward the daily goal. Zero goals mean no target is set.
+	TodayPomodoros int           `json:"today_pomodoros"`
+	TodayFocus     time.Duration `json:"today_focus"`
+	GoalPomodoros  int           `json:"goal_pomodoros"`
+	GoalFocus      time.Duration `json:"goal_focus"`
 }
 
 func getRuntimePath(fileName string) string {
@@ -77,7 +246,17 @@ func GetPidPath() string {
 	return getRuntimePath(PidFileName)
 }
 
+// GetWatchSocketPath returns the path to the socket that streams timer events.
+func GetWatchSocketPath() string {
+	return getRuntimePath(WatchSocketName)
+}
+
 // Dial dials the daemon's RPC server.
 func Dial() (net.Conn, error) {
 	return net.Dial("unix", GetSocketPath())
 }
+
+// DialWatch dials the daemon's event stream.
+func DialWatch() (net.Conn, error) {
+	return net.Dial("unix", GetWatchSocketPath())
+}
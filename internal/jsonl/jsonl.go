@@ -0,0 +1,98 @@
+// Package jsonl stores records as JSON lines in append-only files.
+// Callers serialize access to a file themselves.
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxLine is the longest line Read accepts.
+const maxLine = 1024 * 1024
+
+// RotatedPath returns the path a file is moved to when it is rotated.
+func RotatedPath(path string) string {
+	return path + ".1"
+}
+
+// Append encodes v as JSON and appends it to the file as a line, creating the file and its directory if needed.
+// When maxSize is positive and the file has reached it, the file is first moved to RotatedPath,
+// replacing the previous one, so that at most two files of about maxSize are kept.
+func Append(path string, v any, maxSize int64) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode record: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	if maxSize > 0 {
+		if info, err := os.Stat(path); err == nil && info.Size() >= maxSize {
+			if err := os.Rename(path, RotatedPath(path)); err != nil {
+				return fmt.Errorf("failed to rotate %s: %w", path, err)
+			}
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return file.Close()
+}
+
+// Read decodes the lines of the rotated file, if any, and then of the file, in order,
+// and returns the values keep accepts. A nil keep accepts every value; missing files are treated as empty.
+func Read[T any](path string, keep func(T) bool) ([]T, error) {
+	var values []T
+	for _, p := range []string{RotatedPath(path), path} {
+		var err error
+		if values, err = readFile(p, values, keep); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// readFile appends the values of a single file that keep accepts to values.
+func readFile[T any](path string, values []T, keep func(T) bool) ([]T, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, nil
+		}
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var v T
+		if err := json.Unmarshal(scanner.Bytes(), &v); err != nil {
+			return nil, fmt.Errorf("failed to parse %s at line %d: %w", path, line, err)
+		}
+		if keep == nil || keep(v) {
+			values = append(values, v)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return values, nil
+}
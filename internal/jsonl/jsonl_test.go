@@ -0,0 +1,57 @@
+package jsonl
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type record struct {
+	N int `json:"n"`
+}
+
+func TestAppendRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dir", "records.jsonl")
+
+	records, err := Read[record](path, nil)
+	require.NoError(t, err)
+	assert.Empty(t, records, "a missing file is empty")
+
+	for n := range 3 {
+		require.NoError(t, Append(path, record{N: n}, 0))
+	}
+	records, err = Read[record](path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []record{{0}, {1}, {2}}, records)
+
+	records, err = Read(path, func(r record) bool { return r.N != 1 })
+	require.NoError(t, err)
+	assert.Equal(t, []record{{0}, {2}}, records)
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"n\": 1}\n\nnot json\n"), 0644))
+	_, err = Read[record](path, nil)
+	assert.ErrorContains(t, err, "at line 3")
+}
+
+func TestAppendRotates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "records.jsonl")
+	const maxSize = 20 // Each line is 8 bytes, so the file rotates after 3 lines.
+
+	for n := range 8 {
+		require.NoError(t, Append(path, record{N: n}, maxSize))
+	}
+
+	rotated, err := readFile[record](RotatedPath(path), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []record{{3}, {4}, {5}}, rotated)
+	current, err := readFile[record](path, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []record{{6}, {7}}, current)
+
+	records, err := Read[record](path, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []record{{3}, {4}, {5}, {6}, {7}}, records, "the rotated file is read first")
+}
@@ -0,0 +1,17 @@
+package notify
+
+import (
+	"github.com/gen2brain/beeep"
+)
+
+// Beep plays a beep for every message that has text and is not muted,
+// so that events which are not announced stay silent.
+type Beep struct{}
+
+// Notify implements Notifier.
+func (Beep) Notify(m Message) error {
+	if m.Text == "" || m.Muted {
+		return nil
+	}
+	return beeep.Beep(beeep.DefaultFreq, beeep.DefaultDuration)
+}
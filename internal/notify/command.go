@@ -0,0 +1,49 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/hook"
+)
+
+// Command runs a shell command for every message.
+// The message is passed in PMDR_MESSAGE, PMDR_TITLE and PMDR_BODY,
+// together with the PMDR_* variables that hooks get. PMDR_MUTED is 1 when the message should make no sound.
+type Command struct {
+	Command string
+	Timeout time.Duration // Time limit of the command, after which it is killed with its children; 0 disables it
+}
+
+// Notify implements Notifier.
+func (c Command) Notify(m Message) error {
+	ctx := context.Background()
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	cmd := hook.Command(ctx, c.Command)
+	cmd.Env = append(os.Environ(), m.Context.Env()...)
+	cmd.Env = append(cmd.Env,
+		"PMDR_MESSAGE="+m.Text,
+		"PMDR_TITLE="+m.Title,
+		"PMDR_BODY="+m.Body,
+		"PMDR_MUTED="+muted(m.Muted),
+	)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notification command failed: %w: %s", err, out)
+	}
+	return nil
+}
+
+// muted returns the value of PMDR_MUTED.
+func muted(b bool) string {
+	if b {
+		return "1"
+	}
+	return "0"
+}
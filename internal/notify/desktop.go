@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"github.com/gen2brain/beeep"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+func init() {
+	beeep.AppName = config.ProjectName
+}
+
+// Desktop shows native desktop notifications.
+// Messages without a title or body are shown with the message text; messages with neither are not shown.
+type Desktop struct {
+	Icon string // Used by messages without an icon of their own
+}
+
+// Notify implements Notifier.
+// Critical messages are shown as alerts, which also play a beep, unless they are muted; low urgency is shown as normal.
+func (d Desktop) Notify(m Message) error {
+	if m.Title == "" && m.Body == "" && m.Text == "" {
+		return nil
+	}
+	title, body, icon := m.Title, m.Body, m.Icon
+	if title == "" {
+		title = config.ProjectName
+	}
+	if body == "" {
+		body = m.Text
+	}
+	if icon == "" {
+		icon = d.Icon
+	}
+
+	if m.Urgency == config.UrgencyCritical && !m.Muted {
+		return beeep.Alert(title, body, icon)
+	}
+	return beeep.Notify(title, body, icon)
+}
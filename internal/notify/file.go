@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileMu serializes writes to notification files.
+var fileMu sync.Mutex
+
+// File appends every message to a file as a line with the time and the event.
+type File struct {
+	Path string // A leading "~/" is replaced with the home directory
+}
+
+// Notify implements Notifier.
+func (f File) Notify(m Message) error {
+	path := f.Path
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("could not get user home directory: %w", err)
+		}
+		path = filepath.Join(home, rest)
+	}
+
+	text := m.Text
+	if text == "" {
+		text = strings.TrimSpace(m.Title + " " + m.Body)
+	}
+	line := fmt.Sprintf("%s %s %s\n", time.Now().Format(time.RFC3339), m.Event, text)
+
+	fileMu.Lock()
+	defer fileMu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create notification directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open notification file: %w", err)
+	}
+	if _, err := file.WriteString(line); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write notification: %w", err)
+	}
+	return file.Close()
+}
@@ -0,0 +1,157 @@
+// Package notify delivers notifications to pluggable backends such as text-to-speech or the desktop.
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/hook"
+)
+
+// Message is a notification about a timer event.
+type Message struct {
+	Event   string     // Hook event, e.g. "start"
+	Text    string     // Spoken or written form, e.g. "Work session started."
+	Title   string     // Desktop notification title
+	Body    string     // Desktop notification body
+	Icon    string     // Desktop notification icon
+	Urgency string     // Desktop notification urgency
+	Context hook.Event // Session the message is about
+	Muted   bool       // Delivered without sound; speech and beeps are dropped
+}
+
+// Notifier delivers messages.
+type Notifier interface {
+	Notify(m Message) error
+}
+
+// Func adapts a function to a Notifier.
+type Func func(m Message) error
+
+// Notify implements Notifier.
+func (f Func) Notify(m Message) error {
+	return f(m)
+}
+
+// Chain tries its notifiers in order until one of them succeeds.
+type Chain []Notifier
+
+// Notify implements Notifier. It returns the errors of every notifier if all of them fail.
+func (c Chain) Notify(m Message) error {
+	var errs []error
+	for _, n := range c {
+		err := n.Notify(m)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Multi sends every message to all of its notifiers.
+type Multi []Notifier
+
+// Notify implements Notifier. It returns the errors of the notifiers that failed.
+func (mu Multi) Notify(m Message) error {
+	var errs []error
+	for _, n := range mu {
+		if err := n.Notify(m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Router sends each message to the notifier of its event.
+// Every event is routed, including those without text; backends that need text skip them.
+type Router struct {
+	Default Notifier            // Used by events without a notifier of their own
+	Events  map[string]Notifier // A nil notifier silences the event
+}
+
+// Notify implements Notifier.
+func (r Router) Notify(m Message) error {
+	n, ok := r.Events[m.Event]
+	if !ok {
+		n = r.Default
+	}
+	if n == nil {
+		return nil
+	}
+	return n.Notify(m)
+}
+
+// New builds the notifier described by the configuration.
+// When desktop notifications are enabled, messages with a title are also shown on the desktop.
+func New(cfg *config.Config) (Notifier, error) {
+	router := Router{Events: make(map[string]Notifier)}
+	var err error
+	if router.Default, err = newChain(cfg, cfg.Notify.Chain); err != nil {
+		return nil, err
+	}
+	for event, names := range cfg.Notify.Events {
+		if router.Events[event], err = newChain(cfg, names); err != nil {
+			return nil, err
+		}
+	}
+
+	if !cfg.Desktop.Enabled {
+		return router, nil
+	}
+	desktop := Desktop{Icon: cfg.Desktop.Icon}
+	return Multi{router, Func(func(m Message) error {
+		if m.Title == "" {
+			return nil
+		}
+		return desktop.Notify(m)
+	})}, nil
+}
+
+// newChain builds a chain of the named backends. An empty chain returns nil.
+func newChain(cfg *config.Config, names []string) (Notifier, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	chain := make(Chain, 0, len(names))
+	for _, name := range names {
+		backend, ok := cfg.Notifier(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown notifier %q", name)
+		}
+		n, err := newBackend(cfg, backend)
+		if err != nil {
+			return nil, fmt.Errorf("notifier %q: %w", name, err)
+		}
+		chain = append(chain, n)
+	}
+	return chain, nil
+}
+
+// newBackend creates the notifier for a backend definition.
+// Text-to-speech backends use the tts settings of the configuration;
+// commands and webhooks are limited by hook_timeout like hooks.
+func newBackend(cfg *config.Config, b config.Notifier) (Notifier, error) {
+	switch b.Type {
+	case config.NotifierTTS:
+		return TTS{
+			Engine:  cfg.TTS.Engine,
+			Command: cfg.TTS.Command,
+			Voice:   cfg.TTS.Voice,
+			Rate:    cfg.TTS.Rate,
+			Volume:  cfg.TTS.Volume,
+		}, nil
+	case config.NotifierBeep:
+		return Beep{}, nil
+	case config.NotifierDesktop:
+		return Desktop{}, nil
+	case config.NotifierCommand:
+		return Command{Command: b.Command, Timeout: cfg.HookTimeout}, nil
+	case config.NotifierFile:
+		return File{Path: b.Path}, nil
+	case config.NotifierWebhook:
+		return Webhook{URL: b.URL, Headers: b.Headers, Timeout: cfg.HookTimeout}, nil
+	}
+	return nil, fmt.Errorf("unknown type %q", b.Type)
+}
@@ -0,0 +1,162 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/hook"
+)
+
+// recorder returns a notifier that appends the text of every message to got and fails with err.
+func recorder(got *[]string, err error) Notifier {
+	return Func(func(m Message) error {
+		*got = append(*got, m.Text)
+		return err
+	})
+}
+
+func TestChain(t *testing.T) {
+	var first, second, third []string
+	chain := Chain{recorder(&first, errors.New("no engine")), recorder(&second, nil), recorder(&third, nil)}
+	require.NoError(t, chain.Notify(Message{Text: "hi"}))
+	assert.Equal(t, []string{"hi"}, first)
+	assert.Equal(t, []string{"hi"}, second)
+	assert.Empty(t, third, "stops at the first success")
+
+	failing := Chain{recorder(&first, errors.New("a")), recorder(&first, errors.New("b"))}
+	err := failing.Notify(Message{Text: "hi"})
+	assert.ErrorContains(t, err, "a")
+	assert.ErrorContains(t, err, "b")
+}
+
+func TestRouter(t *testing.T) {
+	var def, warning []string
+	router := Router{
+		Default: recorder(&def, nil),
+		Events: map[string]Notifier{
+			config.EventWarning: recorder(&warning, nil),
+			config.EventStart:   nil,
+		},
+	}
+
+	require.NoError(t, router.Notify(Message{Event: config.EventComplete, Text: "done"}))
+	require.NoError(t, router.Notify(Message{Event: config.EventWarning, Text: "soon"}))
+	require.NoError(t, router.Notify(Message{Event: config.EventStart, Text: "silenced"}))
+	assert.Equal(t, []string{"done"}, def)
+	assert.Equal(t, []string{"soon"}, warning)
+
+	// Events without text still reach backends such as files and webhooks.
+	var events []string
+	router = Router{Events: map[string]Notifier{
+		config.EventPause: Func(func(m Message) error {
+			events = append(events, m.Event)
+			return nil
+		}),
+	}}
+	require.NoError(t, router.Notify(Message{Event: config.EventPause}))
+	assert.Equal(t, []string{config.EventPause}, events)
+}
+
+func TestBeepSkipsTextless(t *testing.T) {
+	// Would beep, which fails without a sound device, if the messages were not skipped.
+	assert.NoError(t, Beep{}.Notify(Message{Event: config.EventPause}))
+	assert.NoError(t, Beep{}.Notify(Message{Event: config.EventStart, Text: "Work session started.", Muted: true}))
+}
+
+func TestFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "notifications.log")
+	f := File{Path: path}
+	require.NoError(t, f.Notify(Message{Event: config.EventStart, Text: "Work session started."}))
+	require.NoError(t, f.Notify(Message{Event: config.EventComplete, Title: "Work complete", Body: "Done"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasSuffix(lines[0], " start Work session started."), lines[0])
+	assert.True(t, strings.HasSuffix(lines[1], " complete Work complete Done"), lines[1])
+}
+
+func TestCommand(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	c := Command{Command: `echo "$PMDR_EVENT $PMDR_SESSION_TYPE $PMDR_MESSAGE $PMDR_MUTED" > ` + out}
+	require.NoError(t, c.Notify(Message{
+		Event:   config.EventStart,
+		Text:    "Work session started.",
+		Context: hook.Event{Event: config.EventStart, SessionType: "work"},
+	}))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "start work Work session started. 0\n", string(data))
+
+	assert.Error(t, Command{Command: "exit 1"}.Notify(Message{Text: "x"}))
+
+	// The timeout kills the children of the shell too, which would otherwise keep the output open.
+	start := time.Now()
+	assert.Error(t, Command{Command: "sleep 5 & sleep 5", Timeout: 100 * time.Millisecond}.Notify(Message{Text: "x"}))
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestWebhook(t *testing.T) {
+	var got map[string]any
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+	}))
+	defer srv.Close()
+
+	w := Webhook{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer token"}}
+	require.NoError(t, w.Notify(Message{
+		Event:   config.EventGoalReached,
+		Text:    "Daily goal reached. Well done.",
+		Context: hook.Event{Event: config.EventGoalReached, SessionType: "work", Cycle: 4},
+	}))
+	assert.Equal(t, "Bearer token", auth)
+	assert.Equal(t, "goal_reached", got["event"])
+	assert.Equal(t, "Daily goal reached. Well done.", got["message"])
+	assert.Equal(t, "work", got["session"].(map[string]any)["session_type"])
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer failing.Close()
+	assert.Error(t, Webhook{URL: failing.URL}.Notify(Message{Text: "x"}))
+}
+
+func TestNew(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifications.log")
+	cfg := &config.Config{
+		Notifiers: map[string]config.Notifier{
+			"log": {Type: config.NotifierFile, Path: path},
+		},
+		Notify: config.Notify{
+			Chain:  []string{"log"},
+			Events: map[string][]string{config.EventWarning: {}},
+		},
+	}
+	n, err := New(cfg)
+	require.NoError(t, err)
+	require.NoError(t, n.Notify(Message{Event: config.EventStart, Text: "Work session started."}))
+	require.NoError(t, n.Notify(Message{Event: config.EventWarning, Text: "2 minutes remaining."}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Work session started.")
+	assert.NotContains(t, string(data), "remaining", "empty chains silence the event")
+
+	cfg.Notify.Chain = []string{"missing"}
+	_, err = New(cfg)
+	assert.ErrorContains(t, err, `unknown notifier "missing"`)
+}
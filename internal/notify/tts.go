@@ -0,0 +1,195 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/tmpl"
+)
+
+// TTS speaks messages with a text-to-speech engine.
+// Messages without text and muted messages are not spoken.
+type TTS struct {
+	Engine  string   // One of config.Engines; empty picks the engine of the OS
+	Command []string // Custom argv used instead of the engine
+	Voice   string
+	Rate    float64 // Relative to the engine's default; 0 keeps it
+	Volume  float64 // Relative to the engine's default; 0 keeps it
+}
+
+// Notify implements Notifier.
+func (s TTS) Notify(m Message) error {
+	if m.Text == "" || m.Muted {
+		return nil
+	}
+
+	if s.engine() == config.EnginePiper && len(s.Command) == 0 {
+		return s.piper(m.Text)
+	}
+	cmd, err := s.command(m.Text)
+	if err != nil {
+		return err
+	}
+	return run(cmd)
+}
+
+// engine returns the engine to use, resolving auto to the engine of the OS.
+func (s TTS) engine() string {
+	if s.Engine != "" && s.Engine != config.EngineAuto {
+		return s.Engine
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		return config.EngineSay
+	case "windows":
+		return config.EnginePowerShell
+	}
+	return config.EngineSpdSay
+}
+
+// command builds the command that speaks the text.
+// Options an engine does not support are ignored.
+func (s TTS) command(text string) (*exec.Cmd, error) {
+	if len(s.Command) > 0 {
+		return s.custom(text)
+	}
+
+	var args []string
+	switch engine := s.engine(); engine {
+	case config.EngineSay:
+		if s.Voice != "" {
+			args = append(args, "-v", s.Voice)
+		}
+		if s.Rate > 0 {
+			args = append(args, "-r", strconv.Itoa(int(175*s.Rate)))
+		}
+		if s.Volume > 0 {
+			// say has no volume option, but understands embedded commands.
+			text = fmt.Sprintf("[[volm %.2f]] %s", min(s.Volume, 1), text)
+		}
+		return exec.Command("say", append(args, text)...), nil
+
+	case config.EngineSpdSay:
+		// -w waits until the message is spoken so that failures are reported.
+		args = append(args, "-w")
+		if s.Voice != "" {
+			args = append(args, "-y", s.Voice)
+		}
+		if s.Rate > 0 {
+			args = append(args, "-r", strconv.Itoa(percent(s.Rate)))
+		}
+		if s.Volume > 0 {
+			args = append(args, "-i", strconv.Itoa(percent(s.Volume)))
+		}
+		return exec.Command("spd-say", append(args, text)...), nil
+
+	case config.EngineEspeak:
+		if s.Voice != "" {
+			args = append(args, "-v", s.Voice)
+		}
+		if s.Rate > 0 {
+			args = append(args, "-s", strconv.Itoa(int(175*s.Rate)))
+		}
+		if s.Volume > 0 {
+			args = append(args, "-a", strconv.Itoa(int(100*s.Volume)))
+		}
+		return exec.Command("espeak-ng", append(args, text)...), nil
+
+	case config.EngineFestival:
+		if s.Voice != "" {
+			args = append(args, "--eval", "(voice_"+s.Voice+")")
+		}
+		if s.Rate > 0 {
+			args = append(args, "--eval", fmt.Sprintf("(Parameter.set 'Duration_Stretch %.2f)", 1/s.Rate))
+		}
+		cmd := exec.Command("festival", append(args, "--tts")...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd, nil
+
+	case config.EnginePowerShell:
+		script := "Add-Type -AssemblyName System.Speech; $s = New-Object System.Speech.Synthesis.SpeechSynthesizer; "
+		if s.Voice != "" {
+			script += "$s.SelectVoice(" + psQuote(s.Voice) + "); "
+		}
+		if s.Rate > 0 {
+			script += fmt.Sprintf("$s.Rate = %d; ", max(-10, min(10, int((s.Rate-1)*10))))
+		}
+		if s.Volume > 0 {
+			script += fmt.Sprintf("$s.Volume = %d; ", int(min(s.Volume, 1)*100))
+		}
+		script += "$s.Speak(" + psQuote(text) + ");"
+		return exec.Command("PowerShell", "-Command", script), nil
+
+	default:
+		return nil, fmt.Errorf("unknown text-to-speech engine %q", engine)
+	}
+}
+
+// custom builds the custom command. Its arguments are rendered as templates;
+// when none of them refers to the message, the message is passed on stdin.
+func (s TTS) custom(text string) (*exec.Cmd, error) {
+	data := config.CommandData{Message: text, Voice: s.Voice, Rate: s.Rate, Volume: s.Volume}
+	args := make([]string, len(s.Command))
+	usesMessage := false
+	for i, arg := range s.Command {
+		usesMessage = usesMessage || strings.Contains(arg, ".Message")
+		var err error
+		if args[i], err = tmpl.Render(arg, data); err != nil {
+			return nil, fmt.Errorf("failed to render text-to-speech command: %w", err)
+		}
+	}
+
+	cmd := exec.Command(args[0], args[1:]...)
+	if !usesMessage {
+		cmd.Stdin = strings.NewReader(text)
+	}
+	return cmd, nil
+}
+
+// piper synthesizes the text with the voice model into a temporary file and plays it with aplay.
+func (s TTS) piper(text string) error {
+	wav, err := os.CreateTemp("", "pmdr-*.wav")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary audio file: %w", err)
+	}
+	_ = wav.Close()
+	defer func() { _ = os.Remove(wav.Name()) }()
+
+	args := []string{"--model", s.Voice, "--output_file", wav.Name()}
+	if s.Rate > 0 {
+		args = append(args, "--length_scale", fmt.Sprintf("%.2f", 1/s.Rate))
+	}
+	synth := exec.Command("piper", args...)
+	synth.Stdin = strings.NewReader(text)
+	if err := run(synth); err != nil {
+		return err
+	}
+	return run(exec.Command("aplay", "-q", wav.Name()))
+}
+
+// run runs a text-to-speech command and includes its output in the error.
+func run(cmd *exec.Cmd) error {
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run %s: %w: %s", cmd.Args[0], err, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// percent converts a relative value to the -100 to 100 scale of spd-say, where 0 is the default.
+func percent(v float64) int {
+	return max(-100, min(100, int((v-1)*100)))
+}
+
+// psQuote quotes s as a PowerShell string literal.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
@@ -0,0 +1,86 @@
+package notify
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
+)
+
+func TestTTSCommand(t *testing.T) {
+	tests := []struct {
+		name  string
+		tts   TTS
+		args  []string
+		stdin string
+	}{
+		{
+			name: "espeak-ng",
+			tts:  TTS{Engine: config.EngineEspeak, Voice: "en-us", Rate: 1.2, Volume: 0.5},
+			args: []string{"espeak-ng", "-v", "en-us", "-s", "210", "-a", "50", "Hello"},
+		},
+		{
+			name: "spd-say",
+			tts:  TTS{Engine: config.EngineSpdSay, Rate: 1.5, Volume: 3},
+			args: []string{"spd-say", "-w", "-r", "50", "-i", "100", "Hello"},
+		},
+		{
+			name: "say",
+			tts:  TTS{Engine: config.EngineSay, Voice: "Samantha", Volume: 0.5},
+			args: []string{"say", "-v", "Samantha", "[[volm 0.50]] Hello"},
+		},
+		{
+			name:  "festival",
+			tts:   TTS{Engine: config.EngineFestival, Voice: "kal_diphone"},
+			args:  []string{"festival", "--eval", "(voice_kal_diphone)", "--tts"},
+			stdin: "Hello",
+		},
+		{
+			name: "custom",
+			tts:  TTS{Engine: config.EngineEspeak, Command: []string{"mimic", "-voice", "{{.Voice}}", "-t", "{{.Message}}"}, Voice: "slt"},
+			args: []string{"mimic", "-voice", "slt", "-t", "Hello"},
+		},
+		{
+			name:  "custom on stdin",
+			tts:   TTS{Command: []string{"my-tts", "--rate", "{{.Rate}}"}, Rate: 1.5},
+			args:  []string{"my-tts", "--rate", "1.5"},
+			stdin: "Hello",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, err := tt.tts.command("Hello")
+			require.NoError(t, err)
+			assert.Equal(t, tt.args, cmd.Args)
+			if tt.stdin == "" {
+				assert.Nil(t, cmd.Stdin)
+				return
+			}
+			require.NotNil(t, cmd.Stdin)
+			stdin, err := io.ReadAll(cmd.Stdin)
+			require.NoError(t, err)
+			assert.Equal(t, tt.stdin, string(stdin))
+		})
+	}
+
+	_, err := TTS{Engine: "sam"}.command("Hello")
+	assert.Error(t, err)
+}
+
+func TestTTSNotify(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "spoken")
+	tts := TTS{Command: []string{"sh", "-c", `echo "$0" > ` + out, "{{.Message}}"}}
+	require.NoError(t, tts.Notify(Message{Text: "Work session started."}))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "Work session started.\n", string(data))
+
+	assert.Error(t, TTS{Command: []string{"false"}}.Notify(Message{Text: "x"}))
+	assert.NoError(t, TTS{Command: []string{"false"}}.Notify(Message{}), "nothing to say")
+	assert.NoError(t, TTS{Command: []string{"false"}}.Notify(Message{Text: "x", Muted: true}), "muted")
+}
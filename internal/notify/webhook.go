@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/hook"
+)
+
+// Webhook posts every message as JSON.
+// Failed requests are retried like webhook hooks.
+type Webhook struct {
+	URL     string
+	Headers map[string]string
+	Timeout time.Duration // Time limit of the request and its retries; 0 disables it
+}
+
+// payload is the JSON document posted by Webhook.
+type payload struct {
+	Event   string     `json:"event"`
+	Message string     `json:"message,omitempty"`
+	Title   string     `json:"title,omitempty"`
+	Body    string     `json:"body,omitempty"`
+	Session hook.Event `json:"session"`
+}
+
+// Notify implements Notifier.
+func (w Webhook) Notify(m Message) error {
+	data, err := json.Marshal(payload{Event: m.Event, Message: m.Text, Title: m.Title, Body: m.Body, Session: m.Context})
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	ctx := context.Background()
+	if w.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.Timeout)
+		defer cancel()
+	}
+	_, err = hook.Post(ctx, config.HookCommand{
+		Type:    config.HookWebhook,
+		URL:     w.URL,
+		Headers: w.Headers,
+		Retries: config.DefaultWebhookRetries,
+	}, data)
+	return err
+}
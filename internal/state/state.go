@@ -0,0 +1,129 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// FileName is the name of the snapshot file inside the state directory.
+const FileName = "state.json"
+
+// Snapshot is a serializable copy of the timer state.
+type Snapshot struct {
+	State            ipc.SessionState `json:"state"`
+	SessionType      ipc.SessionType  `json:"session_type"`
+	StartSessionTime time.Time        `json:"start_session_time"`
+	NextSessionTime  time.Time        `json:"next_session_time"`
+	PauseTime        time.Time        `json:"pause_time"`
+	PomoCycle        int              `json:"pomo_cycle"`
+	PlannedDuration  time.Duration    `json:"planned_duration"`
+	PausedDuration   time.Duration    `json:"paused_duration"`
+	Profile          string           `json:"profile,omitempty"`
+	Sequence         string           `json:"sequence,omitempty"`
+	Step             int              `json:"step,omitempty"`
+	Task             string           `json:"task,omitempty"`
+	Tags             []string         `json:"tags,omitempty"`
+	SessionConfig    *config.Config   `json:"session_config,omitempty"`
+	Tally            Tally            `json:"tally"`
+	RemainingFired   bool             `json:"remaining_fired,omitempty"`
+	Warned           []time.Duration  `json:"warned,omitempty"` // Warning thresholds already reached
+	Muted            bool             `json:"muted,omitempty"`
+	MutedUntil       time.Time        `json:"muted_until,omitzero"` // Zero mutes until unmuted
+	SavedAt          time.Time        `json:"saved_at"`
+}
+
+// Tally counts the work done on a single day.
+type Tally struct {
+	Date        string        `json:"date"` // Local date in 2006-01-02 form
+	Pomodoros   int           `json:"pomodoros"`
+	Focus       time.Duration `json:"focus"`
+	GoalReached bool          `json:"goal_reached"`
+}
+
+// Store reads and writes snapshots to a file.
+type Store struct {
+	path string
+}
+
+// NewStore creates a new Store backed by the given file.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// DefaultPath returns the default location of the snapshot file.
+func DefaultPath() (string, error) {
+	dir, err := config.GetStateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Path returns the path of the snapshot file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the last saved snapshot.
+// It returns nil without an error if no snapshot has been saved yet.
+func (s *Store) Load() (*Snapshot, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+	return &snap, nil
+}
+
+// Save writes the snapshot to disk.
+// The file is replaced atomically so a crash never leaves a partial snapshot behind.
+func (s *Store) Save(snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), FileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+	defer func() {
+		// Only matters when something below failed; after the rename the file is gone.
+		_ = os.Remove(tmp.Name())
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tsuperis3112/pmdr/internal/config"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("load without a saved snapshot", func(t *testing.T) {
+		store := NewStore(filepath.Join(t.TempDir(), FileName))
+
+		snap, err := store.Load()
+		require.NoError(t, err)
+		assert.Nil(t, snap)
+	})
+
+	t.Run("save and load round trip", func(t *testing.T) {
+		store := NewStore(filepath.Join(t.TempDir(), "nested", FileName))
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		want := &Snapshot{
+			State:            ipc.StatePaused,
+			SessionType:      ipc.TypeShortBreak,
+			StartSessionTime: now,
+			NextSessionTime:  now.Add(5 * time.Minute),
+			PauseTime:        now.Add(time.Minute),
+			PomoCycle:        3,
+			SessionConfig: &config.Config{
+				WorkDuration:       25 * time.Minute,
+				ShortBreakDuration: 5 * time.Minute,
+				LongBreakDuration:  15 * time.Minute,
+				PomoCycles:         4,
+				Hooks:              config.Hook{config.EventComplete: {"work": {{Command: "echo done"}}}},
+			},
+			SavedAt: now.Add(time.Minute),
+		}
+
+		require.NoError(t, store.Save(want))
+		got, err := store.Load()
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+}
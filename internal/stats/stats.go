@@ -0,0 +1,89 @@
+package stats
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsuperis3112/pmdr/internal/history"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+// Period is the length of time that entries are grouped by.
+type Period string
+
+const (
+	ByDay   Period = "day"
+	ByWeek  Period = "week"
+	ByMonth Period = "month"
+)
+
+// ParsePeriod returns the period with the given name.
+func ParsePeriod(name string) (Period, error) {
+	switch p := Period(name); p {
+	case ByDay, ByWeek, ByMonth:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unknown period %q (expected day, week or month)", name)
+	}
+}
+
+// key returns the label of the period containing t, in local time.
+func (p Period) key(t time.Time) string {
+	t = t.Local()
+	switch p {
+	case ByWeek:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case ByMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// Row holds the figures for a single period.
+type Row struct {
+	Period         string
+	Pomodoros      int           // Completed work sessions
+	WorkSessions   int           // Work sessions that ended in any way
+	Focus          time.Duration // Time spent working, excluding pauses
+	Break          time.Duration // Time spent on breaks, excluding pauses
+	AverageSession time.Duration // Average focus time per work session
+	CompletionRate float64       // Share of work sessions that were completed, from 0 to 1
+}
+
+// Compute groups the entries by period and returns one row per period in chronological order.
+func Compute(entries []history.Entry, by Period) []Row {
+	var rows []Row
+	index := make(map[string]int)
+
+	for _, e := range entries {
+		key := by.key(e.Start)
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, Row{Period: key})
+		}
+
+		row := &rows[i]
+		if e.Type == ipc.TypeWork {
+			row.WorkSessions++
+			row.Focus += e.Actual
+			if e.Outcome == history.OutcomeCompleted {
+				row.Pomodoros++
+			}
+		} else {
+			row.Break += e.Actual
+		}
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.WorkSessions > 0 {
+			row.AverageSession = row.Focus / time.Duration(row.WorkSessions)
+			row.CompletionRate = float64(row.Pomodoros) / float64(row.WorkSessions)
+		}
+	}
+	return rows
+}
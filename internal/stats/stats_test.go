@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tsuperis3112/pmdr/internal/history"
+	"github.com/tsuperis3112/pmdr/internal/ipc"
+)
+
+func TestCompute(t *testing.T) {
+	entry := func(start time.Time, st ipc.SessionType, actual time.Duration, outcome history.Outcome) history.Entry {
+		return history.Entry{Type: st, Start: start, End: start.Add(actual), Actual: actual, Outcome: outcome}
+	}
+	mon := time.Date(2025, 1, 6, 9, 0, 0, 0, time.Local)
+	tue := mon.AddDate(0, 0, 1)
+	nextMon := mon.AddDate(0, 0, 7)
+
+	entries := []history.Entry{
+		entry(mon, ipc.TypeWork, 25*time.Minute, history.OutcomeCompleted),
+		entry(mon.Add(30*time.Minute), ipc.TypeShortBreak, 5*time.Minute, history.OutcomeCompleted),
+		entry(mon.Add(time.Hour), ipc.TypeWork, 10*time.Minute, history.OutcomeStopped),
+		entry(tue, ipc.TypeWork, 25*time.Minute, history.OutcomeCompleted),
+		entry(tue.Add(30*time.Minute), ipc.TypeLongBreak, 15*time.Minute, history.OutcomeSkipped),
+		entry(nextMon, ipc.TypeWork, 20*time.Minute, history.OutcomeSkipped),
+	}
+
+	t.Run("by day", func(t *testing.T) {
+		rows := Compute(entries, ByDay)
+		assert.Equal(t, []Row{
+			{Period: "2025-01-06", Pomodoros: 1, WorkSessions: 2, Focus: 35 * time.Minute, Break: 5 * time.Minute, AverageSession: 17*time.Minute + 30*time.Second, CompletionRate: 0.5},
+			{Period: "2025-01-07", Pomodoros: 1, WorkSessions: 1, Focus: 25 * time.Minute, Break: 15 * time.Minute, AverageSession: 25 * time.Minute, CompletionRate: 1},
+			{Period: "2025-01-13", Pomodoros: 0, WorkSessions: 1, Focus: 20 * time.Minute, AverageSession: 20 * time.Minute, CompletionRate: 0},
+		}, rows)
+	})
+
+	t.Run("by week", func(t *testing.T) {
+		rows := Compute(entries, ByWeek)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, "2025-W02", rows[0].Period)
+		assert.Equal(t, 2, rows[0].Pomodoros)
+		assert.Equal(t, 3, rows[0].WorkSessions)
+		assert.Equal(t, "2025-W03", rows[1].Period)
+	})
+
+	t.Run("by month", func(t *testing.T) {
+		rows := Compute(entries, ByMonth)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, "2025-01", rows[0].Period)
+		assert.Equal(t, 80*time.Minute, rows[0].Focus)
+		assert.Equal(t, 20*time.Minute, rows[0].Break)
+		assert.Equal(t, 0.5, rows[0].CompletionRate)
+	})
+
+	t.Run("no entries", func(t *testing.T) {
+		assert.Empty(t, Compute(nil, ByDay))
+	})
+}
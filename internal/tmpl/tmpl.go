@@ -0,0 +1,204 @@
+// Package tmpl renders the text/template strings used in hooks.
+package tmpl
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+)
+
+// Fields lists the fields of the event a template is rendered with.
+// Check rejects references to any other field so typos are caught when the config loads.
+var Fields = []string{
+	"Event",
+	"SessionType",
+	"Cycle",
+	"StartedAt",
+	"EndedAt",
+	"Duration",
+	"Elapsed",
+	"Remaining",
+	"Step",
+	"Task",
+	"Tags",
+}
+
+// Funcs returns the helper functions available in templates.
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"duration": formatDuration,
+		"spell":    spellDuration,
+		"human":    human,
+		"minutes":  func(d time.Duration) int { return int(d.Round(time.Minute) / time.Minute) },
+		"seconds":  func(d time.Duration) int { return int(d.Round(time.Second) / time.Second) },
+		"clock":    func(t time.Time) string { return t.Local().Format("15:04") },
+		"date":     func(t time.Time) string { return t.Local().Format("2006-01-02") },
+		"timefmt":  func(layout string, t time.Time) string { return t.Local().Format(layout) },
+		"now":      time.Now,
+		"join":     func(sep string, elems []string) string { return strings.Join(elems, sep) },
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+		"shquote":  shquote,
+		"json":     jsonValue,
+	}
+}
+
+// jsonValue encodes v as a JSON value, such as a quoted and escaped string,
+// so that values such as task names can be inserted into JSON documents.
+func jsonValue(v any) (string, error) {
+	var sb strings.Builder
+	enc := json.NewEncoder(&sb)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// shquote quotes s as a single shell word, so that values such as task names
+// can be inserted into commands without being run as shell code.
+func shquote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Parse parses a template with the helper functions.
+func Parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs()).Option("missingkey=error").Parse(text)
+}
+
+// Render parses the template and executes it with data.
+func Render(text string, data any) (string, error) {
+	t, err := Parse("hook", text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Check parses the template and verifies that it only refers to known fields.
+func Check(text string) error {
+	t, err := Parse("hook", text)
+	if err != nil {
+		return err
+	}
+	if t.Tree == nil {
+		return nil
+	}
+	return checkNode(t.Tree.Root, true)
+}
+
+// checkNode verifies the field references in the node and its children.
+// atRoot is false inside range and with blocks, where dot is no longer the event.
+func checkNode(node parse.Node, atRoot bool) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(child, atRoot); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkNode(n.Pipe, atRoot)
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := checkNode(cmd, atRoot); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := checkNode(arg, atRoot); err != nil {
+				return err
+			}
+		}
+	case *parse.ChainNode:
+		return checkNode(n.Node, atRoot)
+	case *parse.FieldNode:
+		if atRoot {
+			return checkField(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			return checkField(n.Ident[1])
+		}
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode, atRoot, atRoot)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode, atRoot, false)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode, atRoot, false)
+	case *parse.TemplateNode:
+		return checkNode(n.Pipe, atRoot)
+	}
+	return nil
+}
+
+// checkBranch verifies the pipeline, the body and the else body of an if, range or with block.
+func checkBranch(n *parse.BranchNode, atRoot, inBody bool) error {
+	if err := checkNode(n.Pipe, atRoot); err != nil {
+		return err
+	}
+	if err := checkNode(n.List, inBody); err != nil {
+		return err
+	}
+	return checkNode(n.ElseList, atRoot)
+}
+
+// checkField returns an error unless name is a field of the event.
+func checkField(name string) error {
+	if slices.Contains(Fields, name) {
+		return nil
+	}
+	return fmt.Errorf("unknown field %q (expected one of %s)", name, strings.Join(Fields, ", "))
+}
+
+// human turns an identifier such as "short_break" into "Short break".
+func human(s string) string {
+	s = strings.ReplaceAll(s, "_", " ")
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// spellDuration spells out a duration rounded to the largest unit, e.g. "2 minutes" or "30 seconds".
+func spellDuration(d time.Duration) string {
+	unit, n := "second", int(d.Round(time.Second)/time.Second)
+	if d >= time.Minute {
+		unit, n = "minute", int(d.Round(time.Minute)/time.Minute)
+	}
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// formatDuration formats a duration without zero units, e.g. "25m", "1h30m" or "45s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d == 0 {
+		return "0s"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
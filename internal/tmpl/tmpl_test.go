@@ -0,0 +1,103 @@
+package tmpl
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	SessionType string
+	StartedAt   time.Time
+	Elapsed     time.Duration
+	Task        string
+	Tags        []string
+}
+
+func TestRender(t *testing.T) {
+	start := time.Date(2025, 6, 2, 9, 5, 0, 0, time.Local)
+	data := event{SessionType: "work", StartedAt: start, Elapsed: 90 * time.Minute, Tags: []string{"docs", "pmdr"}}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"plain", "echo done", "echo done"},
+		{"field", "{{.SessionType}} done", "work done"},
+		{"duration", "{{duration .Elapsed}}", "1h30m"},
+		{"minutes", "{{minutes .Elapsed}}", "90"},
+		{"seconds", "{{seconds .Elapsed}}", "5400"},
+		{"clock", "{{clock .StartedAt}}", "09:05"},
+		{"date", "{{date .StartedAt}}", "2025-06-02"},
+		{"timefmt", `{{timefmt "Jan 2" .StartedAt}}`, "Jun 2"},
+		{"join", `{{join ", " .Tags}}`, "docs, pmdr"},
+		{"upper", "{{upper .SessionType}}", "WORK"},
+		{"human", `{{human "short_break"}}`, "Short break"},
+		{"json", `{"tags": {{json .Tags}}, "text": {{json (printf "%s <%s>" "say \"hi\"" "C:\\")}}}`, `{"tags": ["docs","pmdr"], "text": "say \"hi\" <C:\\>"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Render(tt.text, data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestShquote(t *testing.T) {
+	for _, task := range []string{`x"; rm -rf ~; "`, "it's done; echo $HOME `id`", ""} {
+		command, err := Render("printf %s {{shquote .Task}}", event{Task: task})
+		require.NoError(t, err)
+		out, err := exec.Command("sh", "-c", command).Output()
+		require.NoError(t, err, command)
+		assert.Equal(t, task, string(out), command)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "0s", formatDuration(0))
+	assert.Equal(t, "45s", formatDuration(45*time.Second))
+	assert.Equal(t, "25m", formatDuration(25*time.Minute))
+	assert.Equal(t, "2m30s", formatDuration(150*time.Second))
+	assert.Equal(t, "1h", formatDuration(time.Hour))
+	assert.Equal(t, "1h0m5s", formatDuration(time.Hour+5*time.Second))
+}
+
+func TestSpellDuration(t *testing.T) {
+	assert.Equal(t, "2 minutes", spellDuration(2*time.Minute))
+	assert.Equal(t, "1 minute", spellDuration(70*time.Second))
+	assert.Equal(t, "30 seconds", spellDuration(30*time.Second))
+	assert.Equal(t, "1 second", spellDuration(time.Second))
+}
+
+func TestCheck(t *testing.T) {
+	valid := []string{
+		"echo done",
+		"{{.SessionType}} done after {{duration .Elapsed}}",
+		`{{if .Task}}{{.Task}}{{else}}{{.SessionType}}{{end}}`,
+		`{{range .Tags}}{{.}} {{$.Task}}{{end}}`,
+		`{{with .StartedAt}}{{.Hour}}{{end}}`,
+		"{{.StartedAt.Hour}}",
+	}
+	for _, text := range valid {
+		assert.NoError(t, Check(text), text)
+	}
+
+	invalid := map[string]string{
+		"{{.SessionType":                  "unclosed action",
+		"{{.Elapsd}}":                     `unknown field "Elapsd"`,
+		"{{durration .Elapsed}}":          `function "durration" not defined`,
+		"{{if .Tsk}}x{{end}}":             `unknown field "Tsk"`,
+		"{{range .Tags}}{{$.Tgs}}{{end}}": `unknown field "Tgs"`,
+	}
+	for text, msg := range invalid {
+		err := Check(text)
+		if assert.Error(t, err, text) {
+			assert.Contains(t, err.Error(), msg, text)
+		}
+	}
+}